@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/stevengregory/musing-cli/internal/config"
+	"github.com/stevengregory/musing-cli/internal/docker"
+	"github.com/stevengregory/musing-cli/internal/health"
+)
+
+// Default sections services are grouped into
+const (
+	SectionDocker   = "Docker"
+	SectionDatabase = "Database"
+	SectionAPI      = "API Services"
+	SectionFrontend = "Frontend"
+	SectionServices = "Services"
+	SectionTunnels  = "SSH Tunnel(s)"
+)
+
+// Kinds of stack entries that aren't configured services, matched by section types
+// alongside service types
+const (
+	KindDocker   = "docker"
+	KindDatabase = "database"
+	KindTunnel   = "tunnel" // The production tunnel and remote production checks
+)
+
+// defaultSections group the stack by kind. They follow any configured sections and
+// pick up whatever those don't claim; Services takes everything else
+var defaultSections = []config.MonitorSectionConfig{
+	{Name: SectionDocker, Types: []string{KindDocker}},
+	{Name: SectionDatabase, Types: []string{KindDatabase}},
+	{Name: SectionAPI, Types: []string{"api"}},
+	{Name: SectionFrontend, Types: []string{"frontend"}},
+	{Name: SectionServices},
+	{Name: SectionTunnels, Types: []string{KindTunnel}},
+}
+
+// sectionGroup is a section and the services in it
+type sectionGroup struct {
+	config.MonitorSectionConfig
+	Services []ServiceHealth
+}
+
+// stackChecks returns the health checks for the whole stack (Docker, database,
+// every service and the production tunnel), with a placeholder entry for each in the same order
+func stackChecks(cfg *config.ProjectConfig) ([]ServiceHealth, []health.Check) {
+	sections := stackSections(cfg)
+	services := []ServiceHealth{
+		{Name: ServiceDockerDesktop, Kind: KindDocker}, // Docker Desktop doesn't have a specific port
+		{Name: cfg.Database.Type, Port: cfg.Database.DevPort, Kind: KindDatabase, Compose: cfg.Database.Compose},
+	}
+	checks := []health.Check{
+		health.FuncCheck(ServiceDockerDesktop, docker.Ping),
+		health.DatabaseCheck(cfg.Database.Type, cfg.Database.Type, cfg.Database.DevPort),
+	}
+
+	for _, svc := range cfg.Services {
+		services = append(services, ServiceHealth{
+			Name:     svc.Name,
+			Port:     svc.Port,
+			Kind:     svc.Type,
+			Optional: svc.Optional,
+			Compose:  svc.ComposeService(),
+		})
+		checks = append(checks, serviceCheck(svc))
+	}
+
+	// The production tunnel is only open while deploying, so it never fails the stack
+	tunnelName := "Production"
+	if cfg.Production != nil && cfg.Production.Server != "" {
+		tunnelName = cfg.Production.Server
+	}
+	services = append(services, ServiceHealth{Name: tunnelName, Port: cfg.Database.ProdPort, Kind: KindTunnel, Optional: true})
+	checks = append(checks, health.PortCheck(tunnelName, cfg.Database.ProdPort))
+
+	// Remote production endpoints are shown with the tunnel and, like it, never fail the stack
+	if cfg.Production != nil {
+		for _, rc := range cfg.Production.Checks {
+			services = append(services, ServiceHealth{Name: rc.Name, Kind: KindTunnel, Optional: true})
+			checks = append(checks, health.RemoteHealthCheck(rc.Name, health.RemoteCheck{
+				Host:     rc.Host,
+				TLS:      rc.TLS,
+				URL:      rc.URL,
+				Status:   rc.Status,
+				WarnDays: rc.WarnDays,
+				Timeout:  rc.Timeout,
+			}))
+		}
+	}
+
+	for i := range services {
+		services[i].Section = sectionFor(sections, services[i].Name, services[i].Kind)
+	}
+	return services, checks
+}
+
+// stackSections returns the configured sections followed by the default sections,
+// skipping defaults a configured section replaces by name
+func stackSections(cfg *config.ProjectConfig) []config.MonitorSectionConfig {
+	sections := slices.Clone(cfg.Monitor.Sections)
+	for _, def := range defaultSections {
+		if !slices.ContainsFunc(sections, func(s config.MonitorSectionConfig) bool { return s.Name == def.Name }) {
+			sections = append(sections, def)
+		}
+	}
+	return sections
+}
+
+// sectionFor returns the first section holding a service by name, then by kind,
+// falling back to the Services section
+func sectionFor(sections []config.MonitorSectionConfig, name, kind string) string {
+	for _, section := range sections {
+		if slices.Contains(section.Services, name) {
+			return section.Name
+		}
+	}
+	for _, section := range sections {
+		if kind != "" && slices.Contains(section.Types, kind) {
+			return section.Name
+		}
+	}
+	return SectionServices
+}
+
+// sectionIndex returns a section's position in the display order
+func sectionIndex(sections []config.MonitorSectionConfig, name string) int {
+	return slices.IndexFunc(sections, func(s config.MonitorSectionConfig) bool { return s.Name == name })
+}
+
+// groupSections groups services by their section, in display order, leaving out empty sections
+func groupSections(sections []config.MonitorSectionConfig, services []ServiceHealth) []sectionGroup {
+	var groups []sectionGroup
+	for _, section := range sections {
+		group := sectionGroup{MonitorSectionConfig: section}
+		for _, svc := range services {
+			if svc.Section == section.Name {
+				group.Services = append(group.Services, svc)
+			}
+		}
+		if len(group.Services) > 0 {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// title renders a section header: icon, name and how many of its services are running
+func (g sectionGroup) title() string {
+	running := 0
+	for _, svc := range g.Services {
+		if svc.Status == health.StatusRunning {
+			running++
+		}
+	}
+
+	name := g.Name
+	if g.Icon != "" {
+		name = g.Icon + " " + name
+	}
+	return fmt.Sprintf("━━━ %s (%d/%d) ━━━", name, running, len(g.Services))
+}
+
+// serviceCheck returns a service's health check: its configured HTTP or gRPC
+// healthcheck, or a TCP port check when none is configured
+func serviceCheck(svc config.ServiceConfig) health.Check {
+	hc := svc.Healthcheck
+	switch {
+	case hc == nil:
+		return health.PortCheck(svc.Name, svc.Port)
+	case hc.Type == "grpc":
+		return health.GRPCHealthCheck(svc.Name, health.GRPCCheck{
+			Address:       fmt.Sprintf("localhost:%d", svc.Port),
+			Service:       hc.Service,
+			TLS:           hc.TLS,
+			TLSSkipVerify: hc.TLSSkipVerify,
+			Timeout:       hc.Timeout,
+		})
+	default:
+		return health.HTTPHealthCheck(svc.Name, httpCheckFor(svc))
+	}
+}
+
+// httpCheckFor builds the HTTP check for a service's healthcheck config
+func httpCheckFor(svc config.ServiceConfig) health.HTTPCheck {
+	hc := svc.Healthcheck
+
+	url := hc.URL
+	if url == "" {
+		url = fmt.Sprintf("http://localhost:%d/%s", svc.Port, strings.TrimPrefix(hc.Path, "/"))
+	}
+
+	return health.HTTPCheck{
+		URL:       url,
+		Status:    hc.Status,
+		Body:      hc.Body,
+		JSONField: hc.JSONField,
+		JSONValue: hc.JSONValue,
+		Timeout:   hc.Timeout,
+		Headers:   hc.Headers,
+	}
+}
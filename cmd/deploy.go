@@ -1,15 +1,17 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"os"
-	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 	"github.com/stevengregory/musing-cli/internal/config"
 	"github.com/stevengregory/musing-cli/internal/health"
 	"github.com/stevengregory/musing-cli/internal/mongo"
+	"github.com/stevengregory/musing-cli/internal/timeline"
 	"github.com/stevengregory/musing-cli/internal/ui"
 )
 
@@ -36,17 +38,17 @@ var deployCmd = &cobra.Command{
 		}
 
 		env, _ := cmd.Flags().GetString("env")
-		return deployData(collection, env)
+		force, _ := cmd.Flags().GetBool("force")
+		return deployData(projectFrom(cmd), collection, env, force)
 	},
 	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
-		// Dynamic completion for collection names
-		config.MustFindProjectRoot()
-		cfg := config.GetConfig()
-		if cfg == nil {
+		// Dynamic completion for collection names (hooks don't run during completion)
+		project, err := config.Load(searchOptions(cmd))
+		if err != nil {
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		}
 
-		collections, err := mongo.DiscoverCollections(cfg.Database.DataDir)
+		collections, err := mongo.DiscoverCollections(project.DataDir())
 		if err != nil {
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		}
@@ -61,6 +63,7 @@ var deployCmd = &cobra.Command{
 
 func init() {
 	deployCmd.Flags().StringP("env", "e", "dev", "Environment: dev or prod")
+	deployCmd.Flags().Bool("force", false, "Deploy even if the database handshake reports a problem")
 
 	// Add completion for env flag
 	deployCmd.RegisterFlagCompletionFunc("env", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -68,17 +71,8 @@ func init() {
 	})
 }
 
-func deployData(collection, env string) error {
-	// Find and load project configuration
-	config.MustFindProjectRoot()
-
-	cfg := config.GetConfig()
-	if cfg == nil {
-		fmt.Println()
-		ui.Error("No configuration loaded")
-		ui.Info("Run 'musing dev' first to initialize the project")
-		os.Exit(1)
-	}
+func deployData(project *config.Project, collection, env string, force bool) error {
+	cfg := project.Config
 
 	fmt.Println(deployHeaderStyle.Render(fmt.Sprintf("%s Deployment - %s", cfg.Database.Type, env)))
 
@@ -98,9 +92,9 @@ func deployData(collection, env string) error {
 			return nil
 		}
 
-		// Check if tunnel is open
-		status := health.CheckPort(port)
-		if !status.Open {
+		// Check the tunnel is open and the database answers through it
+		status := health.CheckDatabase(context.Background(), cfg.Database.Type, "localhost", port, 0)
+		if status.Status == health.StatusDown {
 			ui.Error(fmt.Sprintf("%s tunnel not open on port %d", cfg.Database.Type, port))
 
 			// Generate helpful SSH tunnel command
@@ -108,32 +102,38 @@ func deployData(collection, env string) error {
 			ui.Info(fmt.Sprintf("Open SSH tunnel first: %s", tunnelCmd))
 			return fmt.Errorf("production %s not accessible", cfg.Database.Type)
 		}
-		ui.Success("SSH tunnel is open")
+		if err := verifyDatabase(cfg, status, force); err != nil {
+			return err
+		}
+		ui.Success(fmt.Sprintf("SSH tunnel is open (%s)", status.Summary()))
 	} else {
 		port = cfg.Database.DevPort
 		mongoURI = fmt.Sprintf("mongodb://localhost:%d", port)
 		ui.Info(fmt.Sprintf("Deploying to DEVELOPMENT (localhost:%d)", port))
 
 		// Check if dev database is running
-		status := health.CheckPort(port)
-		if !status.Open {
+		status := health.CheckDatabase(context.Background(), cfg.Database.Type, "localhost", port, 0)
+		if status.Status == health.StatusDown {
 			ui.Error(fmt.Sprintf("%s not running on port %d", cfg.Database.Type, port))
 			ui.Info("Run 'musing dev' first to start the development stack")
 			return fmt.Errorf("development %s not accessible", cfg.Database.Type)
 		}
-		ui.Success(fmt.Sprintf("%s is running", cfg.Database.Type))
+		if err := verifyDatabase(cfg, status, force); err != nil {
+			return err
+		}
+		ui.Success(fmt.Sprintf("%s is running (%s)", cfg.Database.Type, status.Summary()))
 	}
 
-	// Get data directory from project root
-	projectRoot, _ := config.FindProjectRoot() // Already validated above
-	dataDir := filepath.Join(projectRoot, cfg.Database.DataDir)
+	dataDir := project.DataDir()
 
 	fmt.Println()
 
+	start := time.Now()
 	if collection == "all" {
 		ui.Info("Deploying all collections...")
 		if err := mongo.DeployAll(mongoURI, cfg.Database.Name, dataDir); err != nil {
 			ui.Error(fmt.Sprintf("Failed to deploy: %v", err))
+			recordDeploy(project, collection, env, start, err)
 			return err
 		}
 		ui.Success("All collections deployed successfully!")
@@ -141,11 +141,60 @@ func deployData(collection, env string) error {
 		ui.Info(fmt.Sprintf("Deploying collection: %s", collection))
 		if err := mongo.DeployCollection(mongoURI, cfg.Database.Name, collection, dataDir); err != nil {
 			ui.Error(fmt.Sprintf("Failed to deploy: %v", err))
+			recordDeploy(project, collection, env, start, err)
 			return err
 		}
 		ui.Success(fmt.Sprintf("Collection '%s' deployed successfully!", collection))
 	}
+	recordDeploy(project, collection, env, start, nil)
+
+	return nil
+}
 
+// recordDeploy adds a deploy to the project's timeline, shown by 'musing monitor'
+func recordDeploy(project *config.Project, collection, env string, start time.Time, deployErr error) {
+	event := timeline.Event{
+		Time:     time.Now(),
+		Kind:     timeline.KindDeploy,
+		Service:  project.Config.Database.Type,
+		Message:  fmt.Sprintf("deployed %s to %s", collection, env),
+		Level:    timeline.LevelOK,
+		Duration: time.Since(start),
+	}
+	if deployErr != nil {
+		event.Message = fmt.Sprintf("deploy of %s to %s failed: %v", collection, env, deployErr)
+		event.Level = timeline.LevelError
+	}
+
+	// The timeline is informational: failing to record it doesn't fail the deploy
+	if err := timeline.Append(project.TimelinePath(), event); err != nil {
+		ui.Warning(fmt.Sprintf("Failed to record deploy in the timeline: %v", err))
+	}
+}
+
+// verifyDatabase checks a reachable database actually answered its handshake and can take
+// writes. With force, problems are only warned about
+func verifyDatabase(cfg *config.ProjectConfig, status health.DatabaseStatus, force bool) error {
+	if status.Status == health.StatusDegraded {
+		if force {
+			ui.Warning(fmt.Sprintf("Port %d is open but %s did not answer: %v (deploying anyway)", status.Port, cfg.Database.Type, status.Error))
+			return nil
+		}
+		ui.Error(fmt.Sprintf("Port %d is open but %s did not answer: %v", status.Port, cfg.Database.Type, status.Error))
+		ui.Info("Use --force to deploy anyway")
+		return fmt.Errorf("%s not responding", cfg.Database.Type)
+	}
+
+	// mongoimport needs a writable member of the replica set
+	if strings.HasSuffix(status.ReplicaSet, "secondary") {
+		if force {
+			ui.Warning(fmt.Sprintf("Connected to a replica set secondary (%s), deploying anyway", status.ReplicaSet))
+			return nil
+		}
+		ui.Error(fmt.Sprintf("Connected to a replica set secondary (%s)", status.ReplicaSet))
+		ui.Info("Point the port at the primary before deploying, or use --force")
+		return fmt.Errorf("%s is not writable", cfg.Database.Type)
+	}
 	return nil
 }
 
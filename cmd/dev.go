@@ -3,14 +3,11 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"path/filepath"
-	"time"
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
 	"github.com/stevengregory/musing-cli/internal/config"
 	"github.com/stevengregory/musing-cli/internal/docker"
-	"github.com/stevengregory/musing-cli/internal/health"
 	"github.com/stevengregory/musing-cli/internal/ui"
 )
 
@@ -31,7 +28,7 @@ var devCmd = &cobra.Command{
 	Long:  `Start, stop, and manage the development stack with Docker Compose.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Default to start when no subcommand
-		return startServices(false, false)
+		return startServices(projectFrom(cmd), false, false)
 	},
 }
 
@@ -40,7 +37,7 @@ var devStartCmd = &cobra.Command{
 	Short: "Start development stack",
 	Long:  `Start all services in the development stack with Docker Compose.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return startServices(false, false)
+		return startServices(projectFrom(cmd), false, false)
 	},
 }
 
@@ -49,7 +46,7 @@ var devStopCmd = &cobra.Command{
 	Short: "Stop development stack",
 	Long:  `Stop all services in the development stack.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return stopServices()
+		return stopServices(projectFrom(cmd))
 	},
 }
 
@@ -58,7 +55,7 @@ var devRebuildCmd = &cobra.Command{
 	Short: "Rebuild and start development stack",
 	Long:  `Force rebuild all Docker images and start the development stack.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return startServices(true, false)
+		return startServices(projectFrom(cmd), true, false)
 	},
 }
 
@@ -67,16 +64,17 @@ var devLogsCmd = &cobra.Command{
 	Short: "Follow development stack logs",
 	Long:  `Follow logs from all services in the development stack.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		project := projectFrom(cmd)
+
 		// Change to project root
-		projectRoot := config.MustFindProjectRoot()
-		if err := os.Chdir(projectRoot); err != nil {
+		if err := os.Chdir(project.Root); err != nil {
 			return fmt.Errorf("failed to change to project root: %w", err)
 		}
 
 		fmt.Println()
 		ui.Info("Following logs (Ctrl+C to exit)...")
 		fmt.Println()
-		return docker.ComposeLogs(true)
+		return docker.ComposeLogs(composeProject(project), true)
 	},
 }
 
@@ -87,18 +85,15 @@ func init() {
 	devCmd.AddCommand(devLogsCmd)
 }
 
-func stopServices() error {
+func stopServices(project *config.Project) error {
 	fmt.Println(devHeaderStyle.Render("Stopping Development Stack"))
 
 	// Change to project root directory
-	if err := changeToProjectRoot(); err != nil {
-		fmt.Println()
-		ui.Error("Could not find project root")
-		ui.Info("Run this command from inside a project with .musing.yaml")
-		os.Exit(1)
+	if err := os.Chdir(project.Root); err != nil {
+		return fmt.Errorf("failed to change to project root: %w", err)
 	}
 
-	if err := ui.SpinWithBubbles("Stopping all services...", "docker", "compose", "down"); err != nil {
+	if err := ui.SpinWithBubbles("Stopping all services...", "docker", composeProject(project).Args("down")...); err != nil {
 		ui.Error("Failed to stop services")
 		return err
 	}
@@ -108,22 +103,11 @@ func stopServices() error {
 	return nil
 }
 
-// changeToProjectRoot changes the working directory to the main project root
-// (the directory containing compose.yaml), intelligently handling git worktrees
-func changeToProjectRoot() error {
-	projectRoot, err := config.FindProjectRoot()
-	if err != nil {
-		return err
-	}
-	return os.Chdir(projectRoot)
-}
-
-func startServices(rebuild, followLogs bool) error {
+func startServices(project *config.Project, rebuild, followLogs bool) error {
 	fmt.Println(devHeaderStyle.Render("Development Stack"))
 
 	// Change to project root directory
-	projectRoot := config.MustFindProjectRoot()
-	if err := os.Chdir(projectRoot); err != nil {
+	if err := os.Chdir(project.Root); err != nil {
 		return fmt.Errorf("failed to change to project root: %w", err)
 	}
 
@@ -135,16 +119,23 @@ func startServices(rebuild, followLogs bool) error {
 	ui.Success("Docker is running")
 
 	// Check for missing API repositories
-	if err := checkAPIRepos(); err != nil {
+	if err := checkAPIRepos(project); err != nil {
 		return err
 	}
 
+	// Write dev secrets into the .env file compose reads
+	if err := materialiseSecrets(project, "dev"); err != nil {
+		return err
+	}
+
+	compose := composeProject(project)
+
 	// Build images if requested (stop containers first if rebuilding)
 	if rebuild {
-		if err := ui.SpinWithBubbles("Stopping containers for rebuild...", "docker", "compose", "down"); err != nil {
+		if err := ui.SpinWithBubbles("Stopping containers for rebuild...", "docker", compose.Args("down")...); err != nil {
 			// Ignore errors on stop
 		}
-		if err := ui.SpinWithBubbles("Building images (this may take several minutes)...", "docker", "compose", "build", "--no-cache"); err != nil {
+		if err := ui.SpinWithBubbles("Building images (this may take several minutes)...", "docker", compose.Args("build", "--no-cache")...); err != nil {
 			ui.Error("Failed to build images")
 			return err
 		}
@@ -152,37 +143,58 @@ func startServices(rebuild, followLogs bool) error {
 	}
 
 	// Start services
-	if err := ui.SpinWithBubbles("Starting services...", "docker", "compose", "up", "-d"); err != nil {
+	if err := ui.SpinWithBubbles("Starting services...", "docker", compose.Args("up", "-d")...); err != nil {
 		ui.Error("Failed to start services")
 		return err
 	}
 	ui.Success("Services started")
 
-	// Wait for services to be ready
+	// Wait for every service to pass its health check
 	fmt.Println()
 	ui.Info("Waiting for services to be ready...")
-	time.Sleep(5 * time.Second)
+	if err := waitForHealthy(project.Config); err != nil {
+		fmt.Println()
+		ui.Info("Use 'musing dev logs' to see what went wrong")
+		return err
+	}
 
-	// Print service URLs with health checks
-	printServiceStatus()
+	fmt.Println()
+	ui.Success("All services are healthy")
+	printNextSteps()
 
 	// Follow logs if requested
 	if followLogs {
 		fmt.Println()
 		ui.Info("Following logs (Ctrl+C to exit)...")
 		fmt.Println()
-		return docker.ComposeLogs(true)
+		return docker.ComposeLogs(compose, true)
 	}
 
 	return nil
 }
 
-func checkAPIRepos() error {
-	repos := config.GetAPIRepos()
-	var missing []string
+// composeProject returns the compose files and project name for a project
+// composeProject returns the project's compose invocation. Secrets are written to the
+// env file, which compose only finds on its own when it's the compose directory's .env,
+// so an existing env file is always passed explicitly
+func composeProject(project *config.Project) docker.Compose {
+	compose := docker.Compose{
+		Files:   project.ComposeFiles,
+		Project: project.Config.Compose.Project,
+	}
+	envFile := project.EnvFilePath()
+	if _, err := os.Stat(envFile); err == nil {
+		compose.EnvFiles = []string{envFile}
+	}
+	return compose
+}
+
+func checkAPIRepos(project *config.Project) error {
+	repos := project.Repos()
+	var missing []config.Repo
 
 	for _, repo := range repos {
-		if _, err := os.Stat(repo); os.IsNotExist(err) {
+		if _, err := os.Stat(repo.Path); os.IsNotExist(err) {
 			missing = append(missing, repo)
 		}
 	}
@@ -190,12 +202,19 @@ func checkAPIRepos() error {
 	if len(missing) > 0 {
 		fmt.Println()
 		ui.Warning("Missing API repositories:")
+		cloneable := false
 		for _, repo := range missing {
-			fmt.Printf("  • %s\n", filepath.Base(repo))
+			fmt.Printf("  • %s (%s)\n", repo.Name, repo.Path)
+			if repo.URL != "" {
+				cloneable = true
+			}
 		}
 
 		fmt.Println()
 		ui.Info("Docker Compose will fail without these repositories.")
+		if cloneable {
+			ui.Info("Run 'musing repos clone' to clone them.")
+		}
 
 		if !ui.Confirm("Continue anyway?", false) {
 			return fmt.Errorf("cancelled by user")
@@ -205,114 +224,8 @@ func checkAPIRepos() error {
 	return nil
 }
 
-func printServiceStatus() {
-	fmt.Println()
-
-	cfg := config.GetConfig()
-	if cfg == nil {
-		ui.Error("No configuration loaded")
-		return
-	}
-
-	// Check Docker Desktop
-	dockerRunning := docker.CheckRunning() == nil
-
-	// Organize services by type
-	var apis, frontends []config.ServiceConfig
-	for _, svc := range cfg.Services {
-		switch svc.Type {
-		case "api":
-			apis = append(apis, svc)
-		case "frontend":
-			frontends = append(frontends, svc)
-		}
-	}
-
-	// Define styles
-	sectionHeaderStyle := lipgloss.NewStyle().
-		Bold(true).
-		Foreground(lipgloss.Color("#FF00FF"))
-
-	checkmarkStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#00FF00")).
-		Bold(true)
-
-	errorStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FF0000")).
-		Bold(true)
-
-	// Docker section
-	fmt.Println(sectionHeaderStyle.Render("━━━ Docker ━━━"))
-	fmt.Println()
-	if dockerRunning {
-		fmt.Printf("  %s %-25s\n",
-			checkmarkStyle.Render("✓"),
-			"Docker Desktop")
-	} else {
-		fmt.Printf("  %s %-25s\n",
-			errorStyle.Render("✗"),
-			"Docker Desktop")
-	}
-	fmt.Println()
-
-	// Database section (from database config)
-	fmt.Println(sectionHeaderStyle.Render("━━━ Database ━━━"))
-	fmt.Println()
-	dbStatus := health.CheckPort(cfg.Database.DevPort)
-	if dbStatus.Open {
-		fmt.Printf("  %s %-25s :%-6d\n",
-			checkmarkStyle.Render("✓"),
-			cfg.Database.Type,
-			cfg.Database.DevPort)
-	} else {
-		fmt.Printf("  %s %-25s :%-6d\n",
-			errorStyle.Render("✗"),
-			cfg.Database.Type,
-			cfg.Database.DevPort)
-	}
-	fmt.Println()
-
-	// API Services section
-	if len(apis) > 0 {
-		fmt.Println(sectionHeaderStyle.Render(fmt.Sprintf("━━━ API Services (%d) ━━━", len(apis))))
-		fmt.Println()
-		for _, api := range apis {
-			status := health.CheckPort(api.Port)
-			if status.Open {
-				fmt.Printf("  %s %-25s :%-6d\n",
-					checkmarkStyle.Render("✓"),
-					api.Name,
-					api.Port)
-			} else {
-				fmt.Printf("  %s %-25s :%-6d\n",
-					errorStyle.Render("✗"),
-					api.Name,
-					api.Port)
-			}
-		}
-		fmt.Println()
-	}
-
-	// Frontend section
-	if len(frontends) > 0 {
-		fmt.Println(sectionHeaderStyle.Render("━━━ Frontend ━━━"))
-		fmt.Println()
-		for _, fe := range frontends {
-			status := health.CheckPort(fe.Port)
-			if status.Open {
-				fmt.Printf("  %s %-25s :%-6d\n",
-					checkmarkStyle.Render("✓"),
-					fe.Name,
-					fe.Port)
-			} else {
-				fmt.Printf("  %s %-25s :%-6d\n",
-					errorStyle.Render("✗"),
-					fe.Name,
-					fe.Port)
-			}
-		}
-	}
-
+// printNextSteps prints the commands to use once the stack is up
+func printNextSteps() {
 	fmt.Println()
 	ui.Info("Use 'musing deploy' to populate MongoDB with data")
 	ui.Info("Use 'musing monitor' for live monitoring dashboard")
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/stevengregory/musing-cli/internal/config"
+	"github.com/stevengregory/musing-cli/internal/docker"
+	"github.com/stevengregory/musing-cli/internal/health"
+	"github.com/stevengregory/musing-cli/internal/metrics"
+	"github.com/stevengregory/musing-cli/internal/notify"
+	"github.com/stevengregory/musing-cli/internal/ui"
+)
+
+// exporter serves the latest health check results over HTTP
+type exporter struct {
+	project    *config.Project
+	registry   *metrics.Registry
+	dispatcher *notify.Dispatcher
+
+	mu     sync.Mutex
+	report statusReport
+}
+
+// runExporter runs the health checks on an interval and serves /metrics in the
+// Prometheus text format and /healthz as JSON until interrupted. interval is the
+// --interval value (0: the monitor config's interval or the default)
+func runExporter(project *config.Project, addr string, interval time.Duration) error {
+	dispatcher, err := newDispatcher(project)
+	if err != nil {
+		ui.Error(err.Error())
+		return err
+	}
+
+	e := &exporter{
+		project:    project,
+		registry:   metrics.NewRegistry(filepath.Base(project.Root)),
+		dispatcher: dispatcher,
+	}
+
+	// Listen first so a busy port is reported before anything else happens
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to listen on %s: %v", addr, err))
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", e.serveMetrics)
+	mux.HandleFunc("/healthz", e.serveHealthz)
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	e.check(ctx)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+
+	ui.Success(fmt.Sprintf("Serving metrics on http://%s/metrics", listener.Addr()))
+	interval = checkInterval(project.Config, interval, defaultExporterInterval)
+	ui.Info(fmt.Sprintf("Health summary on http://%s/healthz • checks every %s • Ctrl+C to stop", listener.Addr(), interval))
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			e.check(ctx)
+		case err := <-serveErr:
+			return err
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := server.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return nil
+		}
+	}
+}
+
+// check runs one round of health checks and records the results
+func (e *exporter) check(ctx context.Context) {
+	services, checks := stackChecks(e.project.Config)
+	results := health.Checker{}.Run(ctx, checks)
+	if ctx.Err() != nil {
+		return // Interrupted mid-round: the results are cancellations, not outages
+	}
+	for i, result := range results {
+		e.registry.Observe(services[i].Section, result)
+
+		services[i].Status = result.Status
+		if event := observeResult(e.dispatcher, services[i], result.Error); event != nil {
+			ui.Info(event.Message())
+			go func() {
+				if err := e.dispatcher.Send(ctx, *event); err != nil {
+					ui.Warning(fmt.Sprintf("Notification failed: %v", err))
+				}
+			}()
+		}
+	}
+
+	// Container state is unknown while Docker is down
+	containers, err := docker.ComposePS(composeProject(e.project))
+	if err != nil {
+		containers = nil
+	}
+	e.registry.SetContainers(containers)
+	e.registry.MarkRun(time.Now())
+
+	report := buildStatusReport(filepath.Base(e.project.Root), services, results)
+	e.mu.Lock()
+	e.report = report
+	e.mu.Unlock()
+}
+
+func (e *exporter) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	e.registry.Write(w)
+}
+
+// serveHealthz returns the latest status report, with 503 when a required service is down
+func (e *exporter) serveHealthz(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	report := e.report
+	e.mu.Unlock()
+
+	w.Header().Set("Content-Type", "application/json")
+	if !report.Healthy {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(report)
+}
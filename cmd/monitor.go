@@ -1,27 +1,25 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"os"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	figure "github.com/common-nighthawk/go-figure"
-	"github.com/evertras/bubble-table/table"
 	"github.com/spf13/cobra"
 	"github.com/stevengregory/musing-cli/internal/config"
 	"github.com/stevengregory/musing-cli/internal/docker"
 	"github.com/stevengregory/musing-cli/internal/health"
+	"github.com/stevengregory/musing-cli/internal/notify"
+	"github.com/stevengregory/musing-cli/internal/timeline"
 	"github.com/stevengregory/musing-cli/internal/ui"
 )
 
-// Service name constants
-const (
-	ServiceDockerDesktop = "Docker Desktop"
-	ServiceAngular       = "Angular"
-)
+// ServiceDockerDesktop is the name the Docker check is shown under
+const ServiceDockerDesktop = "Docker Desktop"
 
 // Styles using Lip Gloss
 var (
@@ -56,106 +54,308 @@ var (
 	statusDownStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#FF0000")). // Red
 			Bold(true)
+
+	statusDegradedStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#FFAA00")). // Amber
+				Bold(true)
+
+	detailStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#666666"))
+
+	selectedStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FF00FF")).
+			Bold(true)
 )
 
 // Messages
 type tickMsg time.Time
-type healthCheckMsg struct {
-	services []ServiceHealth
+
+// healthCheckStartedMsg carries the stream of results for a check round
+type healthCheckStartedMsg struct {
+	indexes []int // Service index of each check in the round
+	results <-chan health.Result
 }
 
+// healthResultMsg delivers one check result as soon as it completes
+type healthResultMsg struct {
+	index   int // Index of the checked service
+	result  health.Result
+	indexes []int
+	results <-chan health.Result
+}
+
+// healthCheckDoneMsg signals that every check in the round has reported
+type healthCheckDoneMsg struct{}
+
 type ServiceHealth struct {
-	Name   string
-	Port   int
-	Status string
+	Name      string
+	Port      int
+	Status    string
+	Detail    string           // Extra context, e.g. database version and replica set state
+	Section   string           // Section the service is shown in
+	Kind      string           // Service type, or KindDocker, KindDatabase or KindTunnel
+	Optional  bool             // Being down doesn't make the stack unhealthy
+	Compose   string           // Compose service running it (empty: not a container)
+	Container *containerStatus // Container state and resource usage, when it has one
+	Latency   time.Duration    // Latency of the last check that got an answer
+	CheckedAt time.Time        // When the last check completed
+	Error     string           // Why the last check failed, when down or degraded
+	Backoff   time.Duration    // Check interval while backing off after being down (0 otherwise)
+	Stats     health.Stats     // Uptime, latency and flapping from recorded history
 }
 
 // Model holds the dashboard state
 type monitorModel struct {
-	table      table.Model
-	spinner    spinner.Model
-	lastUpdate time.Time
-	services   []ServiceHealth
-	isChecking bool
-	width      int
-	height     int
+	cfg             *config.ProjectConfig
+	history         *health.History
+	historyPath     string // File history is persisted to (empty: in memory only)
+	dispatcher      *notify.Dispatcher
+	notifyErr       error // Last notification failure
+	compose         docker.Compose
+	checks          []health.Check  // Checks for m.services, in the same order
+	schedules       []checkSchedule // When each service is next checked, in the same order
+	interval        time.Duration
+	paused          bool           // No checks run until resumed
+	cursor          int            // Index of the selected service in display order
+	confirm         *pendingAction // Destructive action awaiting confirmation
+	statusLine      string         // Result of the last action
+	statusErr       bool
+	logs            logPane
+	prod            prodView // Production checked over SSH, with --prod
+	timeline        *timeline.Timeline
+	timelinePane    timelinePane
+	timelinePath    string // File deploys are read from and, with --record, events written to
+	timelineOffset  int64  // How far timelinePath has been read
+	recordEvents    bool
+	exportDir       string                     // Where the timeline is exported to
+	containerEvents <-chan docker.ComposeEvent // nil when docker compose events isn't available
+	exited          map[string]time.Time       // When each compose service's container last exited
+	containers      map[string]containerStatus // Keyed by compose service
+	sampling        bool                       // Container stats are being sampled
+	focusPane       bool                       // Keys go to the open pane rather than the service list
+	thresholds      latencyThresholds
+	sections        []config.MonitorSectionConfig
+	collapsed       map[string]bool // Collapsed sections by name
+	spinner         spinner.Model
+	lastUpdate      time.Time
+	services        []ServiceHealth
+	isChecking      bool
+	width           int
+	height          int
+}
+
+// monitorOptions are the monitor command's flags
+type monitorOptions struct {
+	interval time.Duration // --interval (0: the monitor config's interval, or the mode's default)
+	record   bool          // Persist health history and the event timeline
+	once     bool
+	all      bool // With --plain, print every service each interval
+	prod     bool
+}
+
+// monitorOptionsFrom reads the monitor command's flags
+func monitorOptionsFrom(cmd *cobra.Command) monitorOptions {
+	var opts monitorOptions
+	opts.interval, _ = cmd.Flags().GetDuration("interval")
+	opts.record, _ = cmd.Flags().GetBool("record")
+	opts.once, _ = cmd.Flags().GetBool("once")
+	opts.all, _ = cmd.Flags().GetBool("all")
+	opts.prod, _ = cmd.Flags().GetBool("prod")
+	return opts
 }
 
 var monitorCmd = &cobra.Command{
 	Use:   "monitor",
 	Short: "Live monitoring dashboard for development stack",
-	Long:  `Display a live monitoring dashboard showing the status of Docker, database, API services, and frontend.`,
+	Long: `Display a live monitoring dashboard showing the status of Docker, database, API services, and frontend.
+
+Each service shows its uptime, p50/p95 latency and how long it has held its
+current status. With --record the history is kept in .musing/health.db so it
+carries across sessions and can be summarised with 'musing status --history'.
+
+Press e for a timeline of recent events: status changes, tunnel drops, container
+restarts and deploys. With --record it's kept in .musing/timeline.db too.
+
+With --serve, run headless instead: the checks run on an interval and are
+exposed as Prometheus metrics on /metrics and a JSON summary on /healthz.
+
+With --once or --plain, skip the dashboard and print timestamped status lines
+instead, for logging with tee or under tmux: --once checks once and exits (1 if a
+required service is down), --plain keeps checking and prints each change.
+
+With --prod, production is checked too and shown beside dev: over SSH to
+production.server, the monitor lists the server's containers (docker compose ps),
+checks each service's port there and forwards the database port for a full
+database check. Services are checked on their prodPort (default: their dev port)
+and the database on production.remoteDBPort (default: the standard port for its
+type). SSH runs in batch mode, so key-based login must be set up.`,
+	Example: `  musing monitor
+  musing monitor --record
+  musing monitor --interval 10s
+  musing monitor --prod
+  musing monitor --serve :9099
+  musing monitor --once
+  musing monitor --plain | tee -a health.log`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runMonitor()
+		opts := monitorOptionsFrom(cmd)
+		serve, _ := cmd.Flags().GetString("serve")
+		plain, _ := cmd.Flags().GetBool("plain")
+		if opts.all && !plain {
+			ui.Error("--all only applies with --plain")
+			return fmt.Errorf("--all requires --plain")
+		}
+
+		if serve != "" {
+			return runExporter(projectFrom(cmd), serve, opts.interval)
+		}
+		if opts.once || plain {
+			return runPlain(projectFrom(cmd), opts)
+		}
+		return runMonitor(projectFrom(cmd), opts)
 	},
 }
 
-func runMonitor() error {
-	// Load project configuration first
-	config.MustFindProjectRoot()
+func init() {
+	monitorCmd.Flags().Bool("record", false, "Persist health history and the event timeline in .musing/")
+	monitorCmd.Flags().String("serve", "", "Serve Prometheus metrics on this address (e.g. :9099) instead of the dashboard")
+	monitorCmd.Flags().Duration("interval", 0, "How often to run checks (default 3s, or 10s with --serve)")
+	monitorCmd.Flags().Bool("once", false, "Print each service's status once and exit, without the dashboard")
+	monitorCmd.Flags().Bool("plain", false, "Print timestamped status changes instead of the dashboard")
+	monitorCmd.Flags().Bool("all", false, "With --plain, print every check, not only changes")
+	monitorCmd.Flags().Bool("prod", false, "Also check the production server over SSH and show it beside dev")
+	monitorCmd.MarkFlagsMutuallyExclusive("serve", "once", "plain")
+	monitorCmd.MarkFlagsMutuallyExclusive("prod", "serve")
+	monitorCmd.MarkFlagsMutuallyExclusive("prod", "once")
+	monitorCmd.MarkFlagsMutuallyExclusive("prod", "plain")
+}
 
+func runMonitor(project *config.Project, opts monitorOptions) error {
 	// Check Docker is running (don't auto-start for monitor - just inform user)
 	if err := docker.CheckRunning(); err != nil {
 		fmt.Println()
 		ui.Error("Docker is not running")
 		ui.Info("Please start Docker Desktop and try again, or run: musing dev")
-		os.Exit(1)
+		return err
+	}
+
+	model := initialMonitorModel(project.Config, opts.interval)
+	model.compose = composeProject(project)
+
+	dispatcher, err := newDispatcher(project)
+	if err != nil {
+		ui.Error(err.Error())
+		return err
+	}
+	model.dispatcher = dispatcher
+
+	if opts.record {
+		history, err := health.LoadHistory(project.HealthHistoryPath(), health.DefaultHistorySize)
+		if err != nil {
+			ui.Error(fmt.Sprintf("Failed to load health history: %v", err))
+			return err
+		}
+		model.history = history
+		model.historyPath = project.HealthHistoryPath()
+	}
+
+	events, offset, err := timeline.Load(project.TimelinePath(), timeline.DefaultSize)
+	if err != nil {
+		ui.Error(fmt.Sprintf("Failed to load timeline: %v", err))
+		return err
+	}
+	model.timeline = events
+	model.timelinePath = project.TimelinePath()
+	model.timelineOffset = offset
+	model.recordEvents = opts.record
+	model.exportDir = project.StateDir()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if stream, err := docker.StreamEvents(ctx, model.compose); err == nil {
+		model.containerEvents = stream // Without it the timeline just lacks container events
+	}
+
+	if opts.prod {
+		if project.Config.Production == nil || project.Config.Production.Server == "" {
+			ui.Error("production.server is not set in .musing.yaml")
+			return fmt.Errorf("production configuration not found")
+		}
+		model.prod = newProdView(project.Config)
+
+		forward, port, err := startProdForward(project.Config)
+		if err != nil {
+			ui.Error(err.Error())
+			return err
+		}
+		defer func() {
+			forward.Process.Kill()
+			forward.Wait()
+		}()
+		model.prod.dbPort = port
 	}
 
 	// Create Bubble Tea program with alternate screen
 	p := tea.NewProgram(
-		initialMonitorModel(),
+		model,
 		tea.WithAltScreen(),       // Use alternate screen buffer (no flicker!)
 		tea.WithMouseCellMotion(), // Enable mouse support
 	)
 
-	if _, err := p.Run(); err != nil {
+	final, err := p.Run()
+	if m, ok := final.(monitorModel); ok {
+		m.logs.close()
+	}
+	if err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func initialMonitorModel() monitorModel {
+// initialMonitorModel returns the dashboard for a project, checking every interval
+// (0: the monitor config's interval or the default)
+func initialMonitorModel(cfg *config.ProjectConfig, interval time.Duration) monitorModel {
 	// Create spinner
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF00FF"))
 
-	// Create initial table
-	columns := []table.Column{
-		table.NewColumn("status", "", 3),
-		table.NewColumn("name", "Service", 25),
-		table.NewColumn("port", "Port", 8),
-		table.NewColumn("latency", "Latency", 12),
-	}
-
-	t := table.New(columns).
-		WithRows([]table.Row{}).
-		Focused(false).
-		WithPageSize(20).
-		WithBaseStyle(lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FFFFFF")).
-			BorderForeground(lipgloss.Color("#FF00FF"))).
-		HeaderStyle(lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#FF00FF")).
-			Bold(true)).
-		WithStaticFooter("")
+	services, checks := stackChecks(cfg)
+	interval = checkInterval(cfg, interval, defaultMonitorInterval)
+
+	sections := stackSections(cfg)
+	collapsed := map[string]bool{}
+	for _, section := range sections {
+		collapsed[section.Name] = section.Collapsed
+	}
 
 	return monitorModel{
-		table:      t,
+		cfg:        cfg,
+		history:    health.NewHistory(health.DefaultHistorySize),
+		timeline:   timeline.New(timeline.DefaultSize),
+		exited:     map[string]time.Time{},
+		thresholds: thresholdsFor(cfg),
+		sections:   sections,
+		collapsed:  collapsed,
 		spinner:    s,
 		lastUpdate: time.Now(),
-		services:   []ServiceHealth{},
-		isChecking: false,
+		services:   services,
+		checks:     checks,
+		schedules:  stackSchedules(cfg, services, interval),
+		interval:   interval,
+		isChecking: true, // Init runs the first round
+		sampling:   true, // ...and takes the first sample
 	}
 }
 
 func (m monitorModel) Init() tea.Cmd {
 	return tea.Batch(
 		m.spinner.Tick,
-		tickCmd(),
-		checkHealthCmd(), // Initial health check
+		tickCmd(m.tickInterval()),
+		checkHealthCmd(m.checks, allChecks(m.checks), checkDeadline(m.interval)), // Initial health check
+		containerStatsCmd(m.compose),
+		waitForContainerEvent(m.containerEvents),
+		m.prodCheckCmd(),
 	)
 }
 
@@ -165,28 +365,96 @@ func (m monitorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "ctrl+c", "esc":
-			return m, tea.Quit
+		return m.handleKey(msg.String())
+
+	case actionDoneMsg:
+		if msg.err != nil {
+			m.setStatus(fmt.Sprintf("Failed to %s %s: %v", msg.action.command, msg.service.Name, msg.err), true)
+		} else {
+			m.setStatus(fmt.Sprintf("%s %s", msg.action.done, msg.service.Name), false)
+		}
+		return m, m.recheckCmd(msg.service)
+
+	case serviceCheckedMsg:
+		if m.statusLine == fmt.Sprintf("Checking %s...", msg.service.Name) {
+			m.setStatus(fmt.Sprintf("%s is %s", msg.service.Name, msg.result.Status), msg.result.Status == health.StatusDown)
+		}
+		return m, m.applyResult(m.serviceIndex(msg.service), msg.result)
+
+	case logLinesMsg:
+		if msg.gen != m.logs.gen {
+			return m, nil // From a stream that has since been replaced
+		}
+		m.logs.append(msg.lines)
+		return m, readLogLines(msg.gen, msg.stream)
+
+	case logEndedMsg:
+		if msg.gen == m.logs.gen {
+			m.logs.ended = true
+			m.logs.err = msg.err
 		}
+		return m, nil
 
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
 
 	case tickMsg:
-		// Update every 3 seconds
 		m.lastUpdate = time.Time(msg)
-		if !m.isChecking {
+		m.tailTimeline()
+		cmds = append(cmds, tickCmd(m.tickInterval()))
+		if m.paused {
+			return m, tea.Batch(cmds...)
+		}
+		if due := dueChecks(m.schedules, m.lastUpdate); !m.isChecking && len(due) > 0 {
 			m.isChecking = true
-			return m, tea.Batch(tickCmd(), checkHealthCmd())
+			cmds = append(cmds, checkHealthCmd(m.checks, due, checkDeadline(m.interval)))
+		}
+		if m.prod.due(m.lastUpdate) {
+			m.prod.checking = true
+			cmds = append(cmds, m.prodCheckCmd())
+		}
+		if !m.sampling {
+			// docker stats takes a couple of seconds, so samples run alongside the checks
+			m.sampling = true
+			cmds = append(cmds, containerStatsCmd(m.compose))
 		}
-		return m, tickCmd()
+		return m, tea.Batch(cmds...)
+
+	case containerEventMsg:
+		if event, ok := containerEvent(msg.event, m.composeName(msg.event.Service), m.exited); ok {
+			m.addEvents(event)
+		}
+		return m, waitForContainerEvent(msg.events)
+
+	case prodCheckedMsg:
+		m.prod.apply(msg, time.Now())
+		return m, nil
 
-	case healthCheckMsg:
-		m.services = msg.services
+	case containerStatsMsg:
+		m.sampling = false
+		m.applyContainerStats(msg)
+		return m, nil
+
+	case healthCheckStartedMsg:
+		return m, waitForHealthResult(msg.indexes, msg.results)
+
+	case healthResultMsg:
+		return m, tea.Batch(waitForHealthResult(msg.indexes, msg.results), m.applyResult(msg.index, msg.result))
+
+	case notifySentMsg:
+		m.notifyErr = msg.err
+		return m, nil
+
+	case healthCheckDoneMsg:
 		m.isChecking = false
-		m.table = m.table.WithRows(m.buildTableRows())
+		if m.statusLine == "Refreshing..." {
+			m.setStatus("Refreshed", false)
+		}
+		if m.historyPath != "" {
+			// History is best-effort: a failed write shouldn't interrupt monitoring
+			_ = m.history.Append(m.historyPath)
+		}
 		return m, nil
 
 	case spinner.TickMsg:
@@ -197,261 +465,333 @@ func (m monitorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
-func (m monitorModel) View() string {
-	var s string
+// applyResult records a check result for the service at index i, returning a
+// command to send a notification if the service changed status
+func (m *monitorModel) applyResult(i int, result health.Result) tea.Cmd {
+	if i < 0 || i >= len(m.services) {
+		return nil
+	}
 
-	// ASCII art banner
-	banner := figure.NewFigure("Musing", "", true)
-	bannerStyle := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("99")).
-		Bold(true)
-	s += bannerStyle.Render(banner.String())
-	s += "\n"
-
-	// Header
-	s += headerStyle.Render("Development Stack - Live Monitor")
-	s += "\n"
-
-	// Docker Section
-	dockerServices := m.getDockerServices()
-	if len(dockerServices) > 0 {
-		s += sectionHeaderStyle.Render("━━━ Docker ━━━")
-		s += "\n"
-		s += m.renderServiceList(dockerServices)
-		s += "\n"
-	}
-
-	// Database Section
-	databaseServices := m.getDatabaseServices()
-	if len(databaseServices) > 0 {
-		s += sectionHeaderStyle.Render("━━━ Database ━━━")
-		s += "\n"
-		s += m.renderServiceList(databaseServices)
-		s += "\n"
-	}
-
-	// API Services Section
-	apiServices := m.getAPIServices()
-	if len(apiServices) > 0 {
-		s += sectionHeaderStyle.Render(fmt.Sprintf("━━━ API Services (%d) ━━━", len(apiServices)))
-		s += "\n"
-		s += m.renderServiceList(apiServices)
-		s += "\n"
-	}
-
-	// Frontend Section
-	angularServices := m.getFrontendServices()
-	if len(angularServices) > 0 {
-		s += sectionHeaderStyle.Render("━━━ Frontend ━━━")
-		s += "\n"
-		s += m.renderServiceList(angularServices)
-		s += "\n"
-	}
-
-	// SSH Tunnel(s) Section
-	sshServices := m.getSSHTunnelServices()
-	if len(sshServices) > 0 {
-		s += sectionHeaderStyle.Render("━━━ SSH Tunnel(s) ━━━")
-		s += "\n"
-		s += m.renderServiceList(sshServices)
-		s += "\n"
-	}
-
-	// Footer
-	s += footerStyle.Render("Press q or Ctrl+C to exit • Updates every 3 seconds")
+	if event, ok := transitionEvent(m.services[i], result.Status, time.Now()); ok {
+		m.addEvents(event)
+	}
 
-	return s
-}
+	m.services[i].Status = result.Status
+	m.services[i].Detail = result.Detail
+	m.services[i].Latency = 0
+	m.services[i].CheckedAt = time.Now()
+	m.services[i].Error = ""
+	if result.Status != health.StatusDown {
+		m.services[i].Latency = result.Latency
+	}
+	if result.Error != nil && result.Status != health.StatusRunning {
+		m.services[i].Error = result.Error.Error()
+	}
 
-func (m monitorModel) getDockerServices() []ServiceHealth {
-	var dockerSvcs []ServiceHealth
-	for _, svc := range m.services {
-		if svc.Name == ServiceDockerDesktop {
-			dockerSvcs = append(dockerSvcs, svc)
+	if i < len(m.schedules) {
+		m.schedules[i].record(result.Status, time.Now())
+		m.services[i].Backoff = 0
+		if m.schedules[i].backingOff() {
+			m.services[i].Backoff = m.schedules[i].delay()
 		}
 	}
-	return dockerSvcs
+
+	m.history.Record(health.Sample{
+		Time:    time.Now(),
+		Service: m.services[i].Name,
+		Status:  result.Status,
+		Latency: result.Latency,
+	})
+	m.services[i].Stats = m.history.Stats(m.services[i].Name)
+
+	if event := observeResult(m.dispatcher, m.services[i], result.Error); event != nil {
+		return sendNotificationCmd(m.dispatcher, *event)
+	}
+	return nil
 }
 
-func (m monitorModel) getSSHTunnelServices() []ServiceHealth {
-	var sshSvcs []ServiceHealth
-	cfg := config.GetConfig()
-	if cfg == nil {
-		return sshSvcs
+func (m monitorModel) View() string {
+	width, height := m.viewWidth(), m.viewHeight()
+
+	if m.logs.open && m.logs.maximized {
+		footer := m.footer(width)
+		return m.logs.view(width, height-lipgloss.Height(footer), m.focusPane) + "\n" + footer
+	}
+	if m.timelinePane.open && m.timelinePane.maximized {
+		footer := m.footer(width)
+		return m.timelinePane.view(m.timeline.Events(), width, height-lipgloss.Height(footer), m.focusPane, time.Now()) + "\n" + footer
 	}
 
-	for _, svc := range m.services {
-		// Match production tunnel port
-		if svc.Port == cfg.Database.ProdPort {
-			sshSvcs = append(sshSvcs, svc)
+	header := m.renderHeader()
+
+	// Everything below the services: action status line, log or timeline pane and footer
+	var tail string
+	if m.confirm != nil {
+		tail += statusDegradedStyle.Render(fmt.Sprintf("%s %s? (y/N)", strings.ToUpper(m.confirm.action.command[:1])+m.confirm.action.command[1:], m.confirm.service.Name))
+		tail += "\n"
+	} else if m.statusLine != "" {
+		style := detailStyle
+		if m.statusErr {
+			style = statusDownStyle
 		}
+		tail += style.Render(m.statusLine)
+		tail += "\n"
+	}
+	if m.logs.open {
+		tail += m.logs.view(width, m.paneHeight(), m.focusPane)
+		tail += "\n"
 	}
-	return sshSvcs
+	if m.timelinePane.open {
+		tail += m.timelinePane.view(m.timeline.Events(), width, m.paneHeight(), m.focusPane, time.Now())
+		tail += "\n"
+	}
+	tail += m.footer(width)
+
+	// The services get whatever height is left, scrolling to keep the cursor in view
+	body, cursorLine := m.renderBody(width)
+	available := height - lipgloss.Height(header) - lipgloss.Height(tail)
+	return header + fitBody(body, cursorLine, width, available) + "\n" + tail
 }
 
-func (m monitorModel) getFrontendServices() []ServiceHealth {
-	var frontend []ServiceHealth
-	for _, svc := range m.services {
-		if svc.Name == ServiceAngular {
-			frontend = append(frontend, svc)
+// footer renders the key hints for whichever pane has focus, wrapped to the width
+func (m monitorModel) footer(width int) string {
+	var hints string
+	switch {
+	case m.focusPane && m.logs.open:
+		hints = "↑/↓ scroll • g/G top/bottom • space pause • / search • n/N next/prev match • z maximize • tab services • esc close"
+	case m.logs.open:
+		hints = "↑/↓ select • l logs • L all logs • tab focus logs • z maximize • esc close logs • q quit"
+	case m.focusPane && m.timelinePane.open:
+		hints = "↑/↓ scroll • g/G newest/oldest • x export • z maximize • tab services • esc close"
+	case m.timelinePane.open:
+		hints = "↑/↓ select • tab focus timeline • z maximize • e/esc close timeline • q quit"
+	default:
+		refresh := fmt.Sprintf("Updates every %s", m.interval)
+		if m.paused {
+			refresh = "Paused"
 		}
+		hints = "↑/↓ select • space collapse • R restart • s stop • S start • l logs • L all logs • e timeline • c check • y copy URL • p pause • r refresh • q quit • " + refresh
+	}
+
+	s := footerStyle.Width(width).Render(hints)
+	if m.notifyErr != nil {
+		s += "\n" + statusDegradedStyle.Render("Notification failed: "+m.notifyErr.Error())
 	}
-	return frontend
+	return s
 }
 
-func (m monitorModel) getDatabaseServices() []ServiceHealth {
-	var database []ServiceHealth
-	cfg := config.GetConfig()
-	if cfg == nil {
-		return database
+// viewWidth is the terminal width, with a default until the first WindowSizeMsg
+func (m monitorModel) viewWidth() int {
+	if m.width > 0 {
+		return m.width
 	}
+	return 80
+}
 
-	for _, svc := range m.services {
-		if svc.Name == cfg.Database.Type {
-			database = append(database, svc)
-		}
+// viewHeight is the terminal height, with a default until the first WindowSizeMsg
+func (m monitorModel) viewHeight() int {
+	if m.height > 0 {
+		return m.height
 	}
-	return database
+	return 24
+}
+
+// paneHeight is the height of the log or timeline pane when split with the service list
+func (m monitorModel) paneHeight() int {
+	return max(m.viewHeight()/3, 8)
 }
 
-func (m monitorModel) getAPIServices() []ServiceHealth {
-	var apis []ServiceHealth
-	cfg := config.GetConfig()
-	if cfg == nil {
-		return apis
+// pageSize is how many lines a page scroll moves the open pane
+func (m monitorModel) pageSize() int {
+	height := m.paneHeight()
+	if (m.logs.open && m.logs.maximized) || (m.timelinePane.open && m.timelinePane.maximized) {
+		height = m.viewHeight() - 1
 	}
+	return max(height-4, 1)
+}
 
-	for _, svc := range m.services {
-		// Exclude: database, frontend, docker, and ssh tunnel (check by port)
-		if svc.Name != cfg.Database.Type && svc.Name != ServiceAngular && svc.Name != ServiceDockerDesktop && svc.Port != cfg.Database.ProdPort {
-			apis = append(apis, svc)
+// groups groups the services into sections in display order, attaching container stats
+func (m monitorModel) groups() []sectionGroup {
+	groups := groupSections(m.sections, m.services)
+	for _, group := range groups {
+		for i, svc := range group.Services {
+			if c, ok := m.containers[svc.Compose]; ok && svc.Compose != "" {
+				group.Services[i].Container = &c
+			}
 		}
 	}
-	return apis
+	return groups
+}
+
+// renderCollapsed renders the single line shown for a collapsed section
+func renderCollapsed(group sectionGroup, selected bool) string {
+	line := detailStyle.Render(fmt.Sprintf("▸ %d service(s) hidden • space to expand", len(group.Services)))
+	if selected {
+		return selectedStyle.Render("› ") + line + "\n"
+	}
+	return "  " + line + "\n"
 }
 
-func (m monitorModel) renderServiceList(services []ServiceHealth) string {
+// renderServiceList renders one line per service with a colored status dot,
+// marking the service at index selected (-1 for none)
+func renderServiceList(services []ServiceHealth, selected int, thresholds latencyThresholds) string {
 	var s string
-	for _, svc := range services {
+	for i, svc := range services {
 		// Status indicator
 		var statusIcon string
-		if svc.Status == "running" {
+		switch svc.Status {
+		case health.StatusRunning:
 			statusIcon = statusRunningStyle.Render("●")
-		} else {
+		case health.StatusDegraded:
+			statusIcon = statusDegradedStyle.Render("●")
+		case "":
+			statusIcon = detailStyle.Render("○") // Not checked yet
+		default:
 			statusIcon = statusDownStyle.Render("●")
 		}
 
-		// Service line: ● Service Name       :8080
-		var line string
-		if svc.Port == 0 {
-			// ServiceDockerDesktop doesn't have a port
-			line = fmt.Sprintf("%s %-25s",
-				statusIcon,
-				svc.Name,
-			)
-		} else {
-			line = fmt.Sprintf("%s %-25s :%-6d",
-				statusIcon,
-				svc.Name,
-				svc.Port,
-			)
+		// Service line: ● Service Name       :8080   12ms
+		port := "" // ServiceDockerDesktop doesn't have a port
+		if svc.Port != 0 {
+			port = fmt.Sprintf(":%d", svc.Port)
+		}
+		line := fmt.Sprintf("%s %-25s %-7s %s",
+			statusIcon,
+			svc.Name,
+			port,
+			thresholds.render(svc.Latency),
+		)
+
+		if svc.Status == health.StatusDegraded {
+			line += " " + statusDegradedStyle.Render("degraded")
+		}
+		if svc.Error != "" {
+			line += " " + statusDownStyle.Render(svc.Error)
+		}
+		if svc.Backoff > 0 {
+			line += " " + detailStyle.Render(fmt.Sprintf("retrying every %s", svc.Backoff))
+		}
+		if svc.Detail != "" {
+			line += " " + detailStyle.Render(svc.Detail)
+		}
+		if svc.Stats.Samples > 1 {
+			line += " " + detailStyle.Render(formatStats(svc.Stats))
+			if svc.Stats.Flapping {
+				line += " " + statusDegradedStyle.Render("flapping")
+			}
+		}
+		if !svc.CheckedAt.IsZero() {
+			line += " " + detailStyle.Render(fmt.Sprintf("checked %s ago", time.Since(svc.CheckedAt).Round(time.Second)))
 		}
 
-		s += "  " + line + "\n"
+		if i == selected {
+			s += selectedStyle.Render("› ") + line + "\n"
+		} else {
+			s += "  " + line + "\n"
+		}
+		if svc.Container != nil {
+			s += "    " + formatContainer(svc.Container) + "\n"
+		}
 	}
 	return s
 }
 
-func (m monitorModel) buildTableRows() []table.Row {
-	rows := []table.Row{}
-	for _, svc := range m.services {
-		var statusIcon string
-		if svc.Status == "running" {
-			statusIcon = "●"
-		} else {
-			statusIcon = "✗"
-		}
+// latencyThresholds are the check latencies shown in amber (warn) and red (critical)
+type latencyThresholds struct {
+	warn     time.Duration
+	critical time.Duration
+}
+
+// thresholdsFor returns the configured latency thresholds, with defaults
+func thresholdsFor(cfg *config.ProjectConfig) latencyThresholds {
+	t := latencyThresholds{warn: 250 * time.Millisecond, critical: time.Second}
+	if cfg.Monitor.LatencyWarn > 0 {
+		t.warn = cfg.Monitor.LatencyWarn
+	}
+	if cfg.Monitor.LatencyCritical > 0 {
+		t.critical = cfg.Monitor.LatencyCritical
+	}
+	return t
+}
 
-		rows = append(rows, table.NewRow(table.RowData{
-			"status":  statusIcon,
-			"name":    svc.Name,
-			"port":    fmt.Sprintf(":%d", svc.Port),
-			"latency": svc.Status,
-		}))
+// render formats a latency in a fixed-width column, coloured against the thresholds
+func (t latencyThresholds) render(latency time.Duration) string {
+	if latency <= 0 {
+		return fmt.Sprintf("%-8s", "")
 	}
-	return rows
+	text := fmt.Sprintf("%-8s", health.FormatLatency(latency))
+	switch {
+	case latency >= t.critical:
+		return statusDownStyle.Render(text)
+	case latency >= t.warn:
+		return statusDegradedStyle.Render(text)
+	default:
+		return detailStyle.Render(text)
+	}
+}
+
+// formatStats summarises a service's history: uptime, latency percentiles and time in its current status
+func formatStats(stats health.Stats) string {
+	parts := []string{fmt.Sprintf("%.1f%% up", stats.Uptime)}
+	if stats.P50 > 0 {
+		parts = append(parts, fmt.Sprintf("p50 %s", health.FormatLatency(stats.P50)))
+		parts = append(parts, fmt.Sprintf("p95 %s", health.FormatLatency(stats.P95)))
+	}
+	parts = append(parts, fmt.Sprintf("%s for %s", stats.Status, time.Since(stats.Since).Round(time.Second)))
+	return strings.Join(parts, " • ")
 }
 
 // Commands
-func tickCmd() tea.Cmd {
-	return tea.Tick(3*time.Second, func(t time.Time) tea.Msg {
+func tickCmd(interval time.Duration) tea.Cmd {
+	return tea.Tick(interval, func(t time.Time) tea.Msg {
 		return tickMsg(t)
 	})
 }
 
-func checkHealthCmd() tea.Cmd {
-	return func() tea.Msg {
-		var services []ServiceHealth
-
-		cfg := config.GetConfig()
-		if cfg == nil {
-			return healthCheckMsg{services: services}
-		}
-
-		// Check Docker Desktop
-		dockerRunning := docker.CheckRunning() == nil
-		services = append(services, ServiceHealth{
-			Name:   ServiceDockerDesktop,
-			Port:   0, // Docker Desktop doesn't have a specific port
-			Status: getStatus(dockerRunning),
-		})
-
-		// Check database (dev environment)
-		dbStatus := health.CheckPort(cfg.Database.DevPort)
-		services = append(services, ServiceHealth{
-			Name:   cfg.Database.Type,
-			Port:   cfg.Database.DevPort,
-			Status: getStatus(dbStatus.Open),
-		})
-
-		// Check Production SSH Tunnel (to production database)
-		prodTunnelStatus := health.CheckPort(cfg.Database.ProdPort)
-		var tunnelName string
-		if cfg.Production != nil && cfg.Production.Server != "" {
-			tunnelName = cfg.Production.Server
-		} else {
-			tunnelName = "Production"
-		}
-		services = append(services, ServiceHealth{
-			Name:   tunnelName,
-			Port:   cfg.Database.ProdPort,
-			Status: getStatus(prodTunnelStatus.Open),
-		})
+// prodCheckCmd starts a round of production checks (nil without --prod)
+func (m monitorModel) prodCheckCmd() tea.Cmd {
+	if !m.prod.enabled {
+		return nil
+	}
+	return m.prod.checkCmd(m.cfg)
+}
 
-		// Check all configured services
-		for _, svc := range cfg.Services {
-			status := health.CheckPort(svc.Port)
-			services = append(services, ServiceHealth{
-				Name:   svc.Name,
-				Port:   svc.Port,
-				Status: getStatus(status.Open),
-			})
-		}
+// tickInterval is how often the monitor looks for due checks
+func (m monitorModel) tickInterval() time.Duration {
+	return tickInterval(m.schedules, m.interval)
+}
 
-		return healthCheckMsg{services: services}
+// checkHealthCmd starts a round of concurrent health checks for the services at indexes
+func checkHealthCmd(checks []health.Check, indexes []int, deadline time.Duration) tea.Cmd {
+	round := make([]health.Check, len(indexes))
+	for i, index := range indexes {
+		round[i] = checks[index]
+	}
+	return func() tea.Msg {
+		checker := health.Checker{Deadline: deadline} // Finish before the next tick
+		return healthCheckStartedMsg{
+			indexes: indexes,
+			results: checker.Stream(context.Background(), round),
+		}
 	}
 }
 
-func getStatus(open bool) string {
-	if open {
-		return "running"
+// allChecks returns the index of every check
+func allChecks(checks []health.Check) []int {
+	indexes := make([]int, len(checks))
+	for i := range indexes {
+		indexes[i] = i
 	}
-	return "down"
+	return indexes
 }
 
-func getLatency(status health.PortStatus) string {
-	if !status.Open {
-		return "timeout"
+// waitForHealthResult waits for the next result of a check round
+func waitForHealthResult(indexes []int, results <-chan health.Result) tea.Cmd {
+	return func() tea.Msg {
+		result, ok := <-results
+		if !ok {
+			return healthCheckDoneMsg{}
+		}
+		return healthResultMsg{index: indexes[result.Index], result: result, indexes: indexes, results: results}
 	}
-	return health.FormatLatency(status.Latency)
 }
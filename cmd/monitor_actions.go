@@ -0,0 +1,324 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/atotto/clipboard"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stevengregory/musing-cli/internal/health"
+)
+
+// containerAction is a docker compose command run against the selected service
+type containerAction struct {
+	command string // Compose subcommand: restart, stop or start
+	done    string // Past tense for the status line
+	confirm bool   // Destructive: ask before running
+}
+
+// pageRows is how far page up/down move the cursor
+const pageRows = 10
+
+// containerActions maps monitor keys to container actions
+var containerActions = map[string]containerAction{
+	"R": {command: "restart", done: "Restarted", confirm: true},
+	"s": {command: "stop", done: "Stopped", confirm: true},
+	"S": {command: "start", done: "Started"},
+}
+
+// actionDoneMsg reports the outcome of a container action
+type actionDoneMsg struct {
+	action  containerAction
+	service ServiceHealth
+	err     error
+}
+
+// serviceCheckedMsg delivers the result of re-checking a single service
+type serviceCheckedMsg struct {
+	service ServiceHealth
+	result  health.Result
+}
+
+// monitorRow is a line the cursor can select: a service, or a collapsed section
+type monitorRow struct {
+	section string
+	service *ServiceHealth // nil for a collapsed section
+}
+
+// rows returns the selectable lines in the order they're shown on screen
+func (m monitorModel) rows() []monitorRow {
+	var rows []monitorRow
+	for _, group := range m.groups() {
+		if m.collapsed[group.Name] {
+			rows = append(rows, monitorRow{section: group.Name})
+			continue
+		}
+		for i := range group.Services {
+			rows = append(rows, monitorRow{section: group.Name, service: &group.Services[i]})
+		}
+	}
+	return rows
+}
+
+// selected returns the service under the cursor
+func (m monitorModel) selected() (ServiceHealth, bool) {
+	rows := m.rows()
+	if m.cursor < 0 || m.cursor >= len(rows) || rows[m.cursor].service == nil {
+		return ServiceHealth{}, false
+	}
+	return *rows[m.cursor].service, true
+}
+
+// toggleSection collapses or expands the section under the cursor, keeping the cursor on it
+func (m *monitorModel) toggleSection() {
+	rows := m.rows()
+	if m.cursor < 0 || m.cursor >= len(rows) {
+		return
+	}
+	section := rows[m.cursor].section
+	m.collapsed[section] = !m.collapsed[section]
+
+	for i, row := range m.rows() {
+		if row.section == section {
+			m.cursor = i
+			return
+		}
+	}
+}
+
+// serviceIndex finds a displayed service in m.services
+func (m monitorModel) serviceIndex(svc ServiceHealth) int {
+	for i, s := range m.services {
+		if s.Name == svc.Name && s.Port == svc.Port && s.Section == svc.Section {
+			return i
+		}
+	}
+	return -1
+}
+
+// handleKey handles dashboard key presses
+func (m monitorModel) handleKey(key string) (tea.Model, tea.Cmd) {
+	// A pending confirmation takes the next key: y runs the action, anything else cancels
+	if m.confirm != nil {
+		pending := *m.confirm
+		m.confirm = nil
+		if key == "y" {
+			return m, m.runContainerAction(pending.action, pending.service)
+		}
+		m.setStatus(fmt.Sprintf("Cancelled %s of %s", pending.action.command, pending.service.Name), false)
+		return m, nil
+	}
+
+	if m.focusPane && m.logs.open {
+		return m.handleLogKey(key)
+	}
+	if m.focusPane && m.timelinePane.open {
+		return m.handleTimelineKey(key)
+	}
+
+	switch key {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "esc":
+		if m.logs.open || m.timelinePane.open {
+			m.logs.close()
+			m.timelinePane.open = false
+			return m, nil
+		}
+		return m, tea.Quit
+	case "tab":
+		m.focusPane = m.logs.open || m.timelinePane.open
+		return m, nil
+	case "z":
+		m.logs.maximized = m.logs.open && !m.logs.maximized
+		m.timelinePane.maximized = m.timelinePane.open && !m.timelinePane.maximized
+		return m, nil
+	case "e":
+		if m.timelinePane.open {
+			m.timelinePane.open = false
+			return m, nil
+		}
+		m.logs.close()
+		m.timelinePane = timelinePane{open: true, maximized: m.timelinePane.maximized}
+		m.focusPane = true
+		return m, nil
+	case " ":
+		m.toggleSection()
+		return m, nil
+	case "p":
+		m.paused = !m.paused
+		if m.paused {
+			m.setStatus("Paused • p to resume", false)
+		} else {
+			m.setStatus("Resumed", false)
+		}
+		return m, nil
+	case "r":
+		if m.isChecking {
+			m.setStatus("A check is already running", false)
+			return m, nil
+		}
+		m.isChecking = true
+		m.setStatus("Refreshing...", false)
+		cmds := []tea.Cmd{checkHealthCmd(m.checks, allChecks(m.checks), checkDeadline(m.interval))}
+		if !m.sampling {
+			m.sampling = true
+			cmds = append(cmds, containerStatsCmd(m.compose))
+		}
+		if m.prod.enabled && !m.prod.checking {
+			m.prod.checking = true
+			cmds = append(cmds, m.prodCheckCmd())
+		}
+		return m, tea.Batch(cmds...)
+	case "L":
+		m.timelinePane.open = false
+		m.focusPane = true
+		return m, m.openLogs("", "all services")
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+		return m, nil
+	case "down", "j":
+		if m.cursor < len(m.rows())-1 {
+			m.cursor++
+		}
+		return m, nil
+	case "pgup":
+		m.cursor = max(0, m.cursor-pageRows)
+		return m, nil
+	case "pgdown":
+		m.cursor = max(0, min(m.cursor+pageRows, len(m.rows())-1))
+		return m, nil
+	case "home":
+		m.cursor = 0
+		return m, nil
+	case "end":
+		m.cursor = max(0, len(m.rows())-1)
+		return m, nil
+	}
+
+	svc, ok := m.selected()
+	if !ok {
+		return m, nil
+	}
+
+	if action, ok := containerActions[key]; ok {
+		if svc.Compose == "" {
+			m.setStatus(fmt.Sprintf("%s isn't a compose service", svc.Name), true)
+			return m, nil
+		}
+		if action.confirm {
+			m.confirm = &pendingAction{action: action, service: svc}
+			return m, nil
+		}
+		return m, m.runContainerAction(action, svc)
+	}
+
+	switch key {
+	case "c":
+		m.setStatus(fmt.Sprintf("Checking %s...", svc.Name), false)
+		return m, m.recheckCmd(svc)
+	case "l":
+		if svc.Compose == "" {
+			m.setStatus(fmt.Sprintf("%s has no container logs", svc.Name), true)
+			return m, nil
+		}
+		m.timelinePane.open = false
+		m.focusPane = true
+		return m, m.openLogs(svc.Compose, svc.Name)
+	case "y":
+		url := serviceURL(svc)
+		if url == "" {
+			m.setStatus(fmt.Sprintf("%s has no URL", svc.Name), true)
+			return m, nil
+		}
+		if err := clipboard.WriteAll(url); err != nil {
+			m.setStatus(fmt.Sprintf("Failed to copy %s: %v", url, err), true)
+			return m, nil
+		}
+		m.setStatus(fmt.Sprintf("Copied %s", url), false)
+	}
+
+	return m, nil
+}
+
+// handleLogKey handles a key while the log pane has focus
+func (m monitorModel) handleLogKey(key string) (tea.Model, tea.Cmd) {
+	if key == "ctrl+c" {
+		return m, tea.Quit // Even while typing a search
+	}
+	if !m.logs.searching {
+		switch key {
+		case "q":
+			return m, tea.Quit
+		case "tab":
+			m.focusPane = false
+			return m, nil
+		case "esc":
+			m.logs.close()
+			m.focusPane = false
+			return m, nil
+		}
+	}
+
+	m.logs.handleKey(key, m.pageSize())
+	return m, nil
+}
+
+// pendingAction is a destructive action awaiting confirmation
+type pendingAction struct {
+	action  containerAction
+	service ServiceHealth
+}
+
+// setStatus sets the action status line
+func (m *monitorModel) setStatus(text string, isErr bool) {
+	m.statusLine = text
+	m.statusErr = isErr
+}
+
+// runContainerAction runs a compose command against a service's container
+func (m monitorModel) runContainerAction(action containerAction, svc ServiceHealth) tea.Cmd {
+	compose := m.compose
+	return func() tea.Msg {
+		output, err := compose.Command(action.command, svc.Compose).CombinedOutput()
+		if err != nil {
+			if msg := lastLine(string(output)); msg != "" {
+				err = fmt.Errorf("%s", msg)
+			}
+		}
+		return actionDoneMsg{action: action, service: svc, err: err}
+	}
+}
+
+// recheckCmd re-runs a single service's health check
+func (m monitorModel) recheckCmd(svc ServiceHealth) tea.Cmd {
+	i := m.serviceIndex(svc)
+	if i < 0 || i >= len(m.checks) {
+		return nil
+	}
+	check, deadline := m.checks[i], checkDeadline(m.interval)
+	return func() tea.Msg {
+		result := health.Checker{Deadline: deadline}.Run(context.Background(), []health.Check{check})[0]
+		return serviceCheckedMsg{service: svc, result: result}
+	}
+}
+
+// serviceURL returns the local address a service is reachable on
+func serviceURL(svc ServiceHealth) string {
+	if svc.Port == 0 || svc.Kind == KindTunnel {
+		return ""
+	}
+	if svc.Kind == "api" || svc.Kind == "frontend" {
+		return fmt.Sprintf("http://localhost:%d", svc.Port)
+	}
+	return fmt.Sprintf("localhost:%d", svc.Port)
+}
+
+// lastLine returns the last non-empty line of command output
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
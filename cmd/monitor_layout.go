@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	figure "github.com/common-nighthawk/go-figure"
+)
+
+// Terminal heights below which the header shrinks
+const (
+	bannerMinHeight  = 40 // Room for the ASCII art banner
+	compactMaxHeight = 30 // Below this, headers drop their margins
+)
+
+// columnGap separates section columns on wide terminals
+const columnGap = 4
+
+var (
+	titleStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#FF00FF"))
+
+	compactSectionStyle = lipgloss.NewStyle().
+				Bold(true).
+				Foreground(lipgloss.Color("#FF00FF"))
+)
+
+// sectionBlock is a rendered section and the line of the cursor in it (-1 if elsewhere)
+type sectionBlock struct {
+	text       string
+	cursorLine int
+}
+
+// renderHeader renders the banner and title, shrinking them on short terminals
+func (m monitorModel) renderHeader() string {
+	height := m.viewHeight()
+
+	switch {
+	case height < compactMaxHeight:
+		return titleStyle.Render("Musing • Development Stack - Live Monitor") + "\n"
+	case height < bannerMinHeight || m.logs.open || m.timelinePane.open:
+		return headerStyle.Render("Development Stack - Live Monitor") + "\n"
+	}
+
+	banner := figure.NewFigure("Musing", "", true)
+	bannerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("99")).
+		Bold(true)
+	return bannerStyle.Render(banner.String()) + "\n" + headerStyle.Render("Development Stack - Live Monitor") + "\n"
+}
+
+// sectionBlocks renders each section, noting where the cursor is
+func (m monitorModel) sectionBlocks() []sectionBlock {
+	style := sectionHeaderStyle
+	if m.viewHeight() < compactMaxHeight {
+		style = compactSectionStyle
+	}
+
+	var blocks []sectionBlock
+	offset := 0
+	for _, group := range m.groups() {
+		header := style.Render(group.title())
+		block := sectionBlock{cursorLine: -1}
+
+		var list string
+		if m.collapsed[group.Name] {
+			list = renderCollapsed(group, m.cursor == offset)
+			if m.cursor == offset {
+				block.cursorLine = 0
+			}
+			offset++
+		} else {
+			list = renderServiceList(group.Services, m.cursor-offset, m.thresholds)
+			if line := selectedLine(group.Services, m.cursor-offset); line >= 0 {
+				block.cursorLine = line
+			}
+			offset += len(group.Services)
+		}
+
+		if block.cursorLine >= 0 {
+			block.cursorLine += lipgloss.Height(header)
+		}
+		block.text = header + "\n" + strings.TrimSuffix(list, "\n")
+		blocks = append(blocks, block)
+	}
+	return blocks
+}
+
+// selectedLine returns the line the service at index selected starts on in
+// renderServiceList output, or -1 if it isn't in the list
+func selectedLine(services []ServiceHealth, selected int) int {
+	if selected < 0 || selected >= len(services) {
+		return -1
+	}
+	line := 0
+	for _, svc := range services[:selected] {
+		line++
+		if svc.Container != nil {
+			line++ // Container stats line
+		}
+	}
+	return line
+}
+
+// layoutBlocks arranges sections in as many columns as fit the width, returning the
+// body and the cursor's line in it (-1 if not shown)
+func layoutBlocks(blocks []sectionBlock, width int) (string, int) {
+	if len(blocks) == 0 {
+		return "", -1
+	}
+
+	blockWidth := 0
+	for _, block := range blocks {
+		blockWidth = max(blockWidth, lipgloss.Width(block.text))
+	}
+	columns := max(1, min(len(blocks), (width+columnGap)/(blockWidth+columnGap)))
+
+	// Fill the shortest column next, which keeps sections in reading order down each column
+	texts := make([][]string, columns)
+	heights := make([]int, columns)
+	cursorLine := -1
+	for _, block := range blocks {
+		col := 0
+		for i := range heights {
+			if heights[i] < heights[col] {
+				col = i
+			}
+		}
+		if block.cursorLine >= 0 {
+			cursorLine = heights[col] + block.cursorLine
+		}
+		texts[col] = append(texts[col], block.text)
+		heights[col] += lipgloss.Height(block.text) + 1 // Blank line between sections
+	}
+
+	if columns == 1 {
+		return strings.Join(texts[0], "\n\n"), cursorLine
+	}
+
+	rendered := make([]string, columns)
+	for i, col := range texts {
+		rendered[i] = lipgloss.NewStyle().Width(blockWidth + columnGap).Render(strings.Join(col, "\n\n"))
+	}
+	return lipgloss.JoinHorizontal(lipgloss.Top, rendered...), cursorLine
+}
+
+// truncateLines truncates each line of s to width
+func truncateLines(s string, width int) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = ansi.Truncate(line, width, "…")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// fitBody clips the body to the terminal: long lines are truncated, and when it's too
+// tall a window around the cursor is shown with markers for what's above and below
+func fitBody(body string, cursorLine, width, height int) string {
+	lines := strings.Split(truncateLines(body, width), "\n")
+
+	height = max(height, 3)
+	if len(lines) <= height {
+		return strings.Join(lines, "\n")
+	}
+
+	start := max(0, min(cursorLine-height/2, len(lines)-height))
+	window := lines[start : start+height]
+	if start > 0 {
+		window[0] = detailStyle.Render(fmt.Sprintf("  ↑ %d more line(s)", start+1))
+	}
+	if below := len(lines) - (start + height); below > 0 {
+		window[len(window)-1] = detailStyle.Render(fmt.Sprintf("  ↓ %d more line(s)", below+1))
+	}
+	return strings.Join(window, "\n")
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestFitBody tests clipping the service list to the height around the cursor
+func TestFitBody(t *testing.T) {
+	var lines []string
+	for i := range 20 {
+		lines = append(lines, strings.Repeat("x", i))
+	}
+	body := strings.Join(lines, "\n")
+
+	fitted := strings.Split(fitBody(body, 10, 80, 6), "\n")
+	if len(fitted) != 6 {
+		t.Fatalf("got %d lines, expected 6", len(fitted))
+	}
+	if !strings.Contains(fitted[0], "↑") || !strings.Contains(fitted[5], "↓") {
+		t.Errorf("expected scroll markers, got %q and %q", fitted[0], fitted[5])
+	}
+	if !strings.Contains(strings.Join(fitted, "\n"), lines[10]+"\n") {
+		t.Errorf("cursor line %q not shown in %q", lines[10], fitted)
+	}
+
+	if fitted := fitBody(body, 0, 5, 30); strings.Contains(fitted, "xxxxxx") {
+		t.Error("lines should be truncated to the width")
+	}
+}
+
+// TestLayoutBlocks tests splitting sections into columns on wide terminals
+func TestLayoutBlocks(t *testing.T) {
+	blocks := []sectionBlock{
+		{text: "Docker\n  one", cursorLine: -1},
+		{text: "API\n  two\n  three", cursorLine: 2},
+		{text: "Frontend\n  four", cursorLine: -1},
+	}
+
+	body, cursor := layoutBlocks(blocks, 12)
+	if lines := strings.Split(body, "\n"); len(lines) != 9 || cursor != 5 {
+		t.Errorf("narrow: %d lines with cursor on %d, expected 9 lines with cursor on 5", len(lines), cursor)
+	}
+
+	body, cursor = layoutBlocks(blocks, 80)
+	if lines := strings.Split(body, "\n"); len(lines) != 3 || cursor != 2 {
+		t.Errorf("wide: %d lines with cursor on %d, expected 3 lines with cursor on 2", len(lines), cursor)
+	}
+}
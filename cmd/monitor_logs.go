@@ -0,0 +1,332 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stevengregory/musing-cli/internal/docker"
+)
+
+// Log pane limits
+const (
+	logPaneLimit = 5000 // Lines kept for scrollback
+	logPaneTail  = 200  // Lines of history loaded when a stream starts
+	logBatchSize = 200  // Most lines delivered in one message
+)
+
+var (
+	logPaneStyle = lipgloss.NewStyle().
+			BorderStyle(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("#FF00FF")).
+			Padding(0, 1)
+
+	logTitleStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FF00FF")).
+			Bold(true)
+
+	logErrorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF0000"))
+	logWarnStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("#FFAA00"))
+	logDebugStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#666666"))
+	logMatchStyle = lipgloss.NewStyle().Reverse(true)
+)
+
+// Log levels are matched on the usual plain, logfmt and JSON spellings
+var (
+	logErrorPattern = regexp.MustCompile(`\b(ERROR|ERRO|FATAL|PANIC)\b|level=(error|fatal)|"level":\s*"(error|fatal)"`)
+	logWarnPattern  = regexp.MustCompile(`\b(WARN|WARNING)\b|level=warn|"level":\s*"warn`)
+	logDebugPattern = regexp.MustCompile(`\b(DEBUG|TRACE)\b|level=(debug|trace)|"level":\s*"(debug|trace)"`)
+)
+
+// logPane follows docker compose logs for one service or the whole stack
+type logPane struct {
+	open      bool
+	gen       int    // Incremented per stream so messages from a replaced stream are dropped
+	service   string // Compose service being followed (empty: all services)
+	title     string
+	stop      context.CancelFunc
+	lines     []string
+	scroll    int  // Lines scrolled back from the newest
+	paused    bool // Keep the view still while new lines arrive
+	search    string
+	searching bool // Typing a search
+	maximized bool
+	ended     bool
+	err       error
+}
+
+// logLinesMsg delivers a batch of log lines from a stream
+type logLinesMsg struct {
+	gen    int
+	lines  []string
+	stream <-chan string
+}
+
+// logEndedMsg signals that a log stream stopped
+type logEndedMsg struct {
+	gen int
+	err error
+}
+
+// openLogs starts following a compose service's logs in the pane (all services when empty)
+func (m *monitorModel) openLogs(service, title string) tea.Cmd {
+	m.logs.close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.logs = logPane{
+		open:      true,
+		gen:       m.logs.gen + 1,
+		service:   service,
+		title:     title,
+		stop:      cancel,
+		maximized: m.logs.maximized,
+	}
+
+	compose, gen := m.compose, m.logs.gen
+	return func() tea.Msg {
+		stream, err := docker.StreamLogs(ctx, compose, service, logPaneTail)
+		if err != nil {
+			return logEndedMsg{gen: gen, err: err}
+		}
+		return readLogLines(gen, stream)()
+	}
+}
+
+// readLogLines waits for the next lines of a log stream, batching lines that are already waiting
+func readLogLines(gen int, stream <-chan string) tea.Cmd {
+	return func() tea.Msg {
+		line, ok := <-stream
+		if !ok {
+			return logEndedMsg{gen: gen}
+		}
+
+		lines := []string{line}
+		for len(lines) < logBatchSize {
+			select {
+			case line, ok := <-stream:
+				if !ok {
+					// Deliver what we have; the next read reports the end
+					return logLinesMsg{gen: gen, lines: lines, stream: stream}
+				}
+				lines = append(lines, line)
+			default:
+				return logLinesMsg{gen: gen, lines: lines, stream: stream}
+			}
+		}
+		return logLinesMsg{gen: gen, lines: lines, stream: stream}
+	}
+}
+
+// close stops the stream and hides the pane
+func (p *logPane) close() {
+	if p.stop != nil {
+		p.stop()
+	}
+	p.open = false
+	p.stop = nil
+}
+
+// append adds lines, keeping the view still when scrolled back or paused
+func (p *logPane) append(lines []string) {
+	for i, line := range lines {
+		lines[i] = ansi.Strip(line)
+	}
+	p.lines = append(p.lines, lines...)
+
+	if p.scroll > 0 || p.paused {
+		p.scroll += len(lines)
+	}
+	if over := len(p.lines) - logPaneLimit; over > 0 {
+		p.lines = append([]string(nil), p.lines[over:]...)
+	}
+	p.scroll = min(p.scroll, max(len(p.lines)-1, 0))
+}
+
+// scrollBy scrolls back (positive) or forward (negative) by n lines
+func (p *logPane) scrollBy(n int) {
+	p.scroll = max(0, min(p.scroll+n, len(p.lines)-1))
+	if p.scroll == 0 {
+		p.paused = false
+	}
+}
+
+// findMatch scrolls to the next search match older (back) or newer than the bottom line
+func (p *logPane) findMatch(back bool) bool {
+	if p.search == "" {
+		return false
+	}
+	search := strings.ToLower(p.search)
+	bottom := len(p.lines) - 1 - p.scroll
+
+	step := 1
+	if back {
+		step = -1
+	}
+	for i := bottom + step; i >= 0 && i < len(p.lines); i += step {
+		if strings.Contains(strings.ToLower(p.lines[i]), search) {
+			p.scroll = len(p.lines) - 1 - i
+			return true
+		}
+	}
+	return false
+}
+
+// matches counts the lines matching the search
+func (p *logPane) matches() int {
+	if p.search == "" {
+		return 0
+	}
+	search := strings.ToLower(p.search)
+	count := 0
+	for _, line := range p.lines {
+		if strings.Contains(strings.ToLower(line), search) {
+			count++
+		}
+	}
+	return count
+}
+
+// handleKey handles a key while the pane has focus, reporting whether it was used
+func (p *logPane) handleKey(key string, page int) bool {
+	if p.searching {
+		switch key {
+		case "enter":
+			p.searching = false
+			if !p.findMatch(true) {
+				p.findMatch(false)
+			}
+		case "esc":
+			p.searching = false
+			p.search = ""
+		case "backspace":
+			if runes := []rune(p.search); len(runes) > 0 {
+				p.search = string(runes[:len(runes)-1])
+			}
+		default:
+			if len([]rune(key)) == 1 {
+				p.search += key
+			}
+		}
+		return true
+	}
+
+	switch key {
+	case "up", "k":
+		p.scrollBy(1)
+	case "down", "j":
+		p.scrollBy(-1)
+	case "pgup", "ctrl+u":
+		p.scrollBy(page)
+	case "pgdown", "ctrl+d":
+		p.scrollBy(-page)
+	case "home", "g":
+		p.scrollBy(len(p.lines))
+	case "end", "G":
+		p.scrollBy(-len(p.lines))
+	case " ":
+		p.paused = !p.paused
+		if !p.paused {
+			p.scroll = 0
+		}
+	case "/":
+		p.searching = true
+		p.search = ""
+	case "n":
+		p.findMatch(true)
+	case "N":
+		p.findMatch(false)
+	case "z":
+		p.maximized = !p.maximized
+	default:
+		return false
+	}
+	return true
+}
+
+// view renders the pane in a box of the given outer size
+func (p logPane) view(width, height int, focused bool) string {
+	// Border and padding take 4 columns and 2 rows, the title one more row
+	innerWidth := max(width-4, 20)
+	rows := max(height-3, 1)
+
+	end := len(p.lines) - p.scroll
+	start := max(0, end-rows)
+
+	var b strings.Builder
+	b.WriteString(p.header(focused))
+	for _, line := range p.lines[start:end] {
+		b.WriteString("\n")
+		b.WriteString(renderLogLine(ansi.Truncate(line, innerWidth, "…"), p.search))
+	}
+	for range rows - (end - start) {
+		b.WriteString("\n")
+	}
+
+	return logPaneStyle.Width(innerWidth + 2).Render(b.String())
+}
+
+// header describes what the pane is showing
+func (p logPane) header(focused bool) string {
+	title := "Logs: " + p.title
+	if focused {
+		title = "▸ " + title
+	}
+
+	var state []string
+	switch {
+	case p.paused:
+		state = append(state, "paused")
+	case p.scroll > 0:
+		state = append(state, fmt.Sprintf("scrolled back %d", p.scroll))
+	case p.ended:
+		state = append(state, "stream ended")
+	default:
+		state = append(state, "following")
+	}
+	if p.err != nil {
+		state = append(state, p.err.Error())
+	}
+	if p.searching {
+		state = append(state, "search: "+p.search+"█")
+	} else if p.search != "" {
+		state = append(state, fmt.Sprintf("%q %d match(es)", p.search, p.matches()))
+	}
+
+	return logTitleStyle.Render(title) + " " + detailStyle.Render(strings.Join(state, " • "))
+}
+
+// renderLogLine colours a line by its log level and highlights search matches
+func renderLogLine(line, search string) string {
+	style := lipgloss.NewStyle()
+	switch {
+	case logErrorPattern.MatchString(line):
+		style = logErrorStyle
+	case logWarnPattern.MatchString(line):
+		style = logWarnStyle
+	case logDebugPattern.MatchString(line):
+		style = logDebugStyle
+	}
+
+	// Highlighting maps match offsets in the lowercased line back onto the original
+	lower, search := strings.ToLower(line), strings.ToLower(search)
+	if search == "" || len(lower) != len(line) {
+		return style.Render(line)
+	}
+
+	var b strings.Builder
+	for {
+		i := strings.Index(lower, search)
+		if i < 0 {
+			break
+		}
+		b.WriteString(style.Render(line[:i]))
+		b.WriteString(logMatchStyle.Render(line[i : i+len(search)]))
+		line, lower = line[i+len(search):], lower[i+len(search):]
+	}
+	b.WriteString(style.Render(line))
+	return b.String()
+}
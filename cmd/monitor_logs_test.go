@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stevengregory/musing-cli/internal/config"
+)
+
+// TestLogPaneScroll tests that the view stays put while scrolled back and scrollback is capped
+func TestLogPaneScroll(t *testing.T) {
+	var p logPane
+	p.append([]string{"one", "two", "three"})
+
+	p.handleKey("up", 10)
+	p.append([]string{"four"})
+	if p.scroll != 2 {
+		t.Errorf("scroll = %d after a new line while scrolled back, want 2", p.scroll)
+	}
+
+	p.handleKey("G", 10)
+	p.append([]string{"five"})
+	if p.scroll != 0 {
+		t.Errorf("scroll = %d when following, want 0", p.scroll)
+	}
+
+	lines := make([]string, logPaneLimit)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d", i)
+	}
+	p.append(lines)
+	if len(p.lines) != logPaneLimit || p.lines[len(p.lines)-1] != lines[len(lines)-1] {
+		t.Errorf("kept %d lines ending %q, want the newest %d", len(p.lines), p.lines[len(p.lines)-1], logPaneLimit)
+	}
+}
+
+// TestLogPaneSearch tests jumping between search matches
+func TestLogPaneSearch(t *testing.T) {
+	var p logPane
+	p.append([]string{"GET /health", "timeout talking to db", "GET /news", "db TIMEOUT again", "GET /health"})
+
+	for _, key := range []string{"/", "t", "i", "m", "e", "o", "u", "t", "enter"} {
+		p.handleKey(key, 10)
+	}
+	if p.search != "timeout" || p.scroll != 1 {
+		t.Fatalf("search %q scrolled to %d, want %q at 1", p.search, p.scroll, "timeout")
+	}
+
+	p.handleKey("n", 10)
+	if p.scroll != 3 {
+		t.Errorf("scroll = %d after n, want the older match at 3", p.scroll)
+	}
+	if p.matches() != 2 {
+		t.Errorf("matches() = %d, want 2", p.matches())
+	}
+}
+
+// TestLogSearchQuit tests that q is typed into a search while ctrl+c still quits
+func TestLogSearchQuit(t *testing.T) {
+	m := initialMonitorModel(&config.ProjectConfig{}, 0)
+	m.logs.open, m.focusPane, m.logs.searching = true, true, true
+
+	model, cmd := m.handleKey("q")
+	m = model.(monitorModel)
+	if cmd != nil || m.logs.search != "q" {
+		t.Errorf("q while searching: search %q, expected it typed", m.logs.search)
+	}
+
+	if _, cmd := m.handleKey("ctrl+c"); cmd == nil {
+		t.Fatal("expected ctrl+c to quit while searching")
+	} else if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("expected ctrl+c to quit while searching")
+	}
+}
+
+// TestLogLevelPatterns tests log level detection for colouring
+func TestLogLevelPatterns(t *testing.T) {
+	tests := []struct {
+		line  string
+		error bool
+		warn  bool
+	}{
+		{line: "2024/01/01 ERROR connection refused", error: true},
+		{line: `time=... level=error msg="db down"`, error: true},
+		{line: `{"level":"fatal","msg":"exit"}`, error: true},
+		{line: "WARN slow query", warn: true},
+		{line: `level=warning msg=retrying`, warn: true},
+		{line: "served 0 errors today"},
+	}
+
+	for _, tt := range tests {
+		if got := logErrorPattern.MatchString(tt.line); got != tt.error {
+			t.Errorf("error match for %q = %v, want %v", tt.line, got, tt.error)
+		}
+		if got := logWarnPattern.MatchString(tt.line); got != tt.warn {
+			t.Errorf("warn match for %q = %v, want %v", tt.line, got, tt.warn)
+		}
+	}
+}
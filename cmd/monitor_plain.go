@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/stevengregory/musing-cli/internal/config"
+	"github.com/stevengregory/musing-cli/internal/health"
+)
+
+// plainTimeFormat timestamps plain output lines
+const plainTimeFormat = "2006-01-02 15:04:05"
+
+// runPlain runs the monitor's checks without the dashboard, printing one timestamped
+// line per service: once, or on every change until interrupted (every check with --all).
+// Checks follow the same schedules as the dashboard: per-service intervals, and backing
+// off services that stay down
+func runPlain(project *config.Project, opts monitorOptions) error {
+	dispatcher, err := newDispatcher(project)
+	if err != nil {
+		return err
+	}
+
+	history := health.NewHistory(health.DefaultHistorySize)
+	if opts.record {
+		if history, err = health.LoadHistory(project.HealthHistoryPath(), health.DefaultHistorySize); err != nil {
+			return fmt.Errorf("failed to load health history: %w", err)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	interval := checkInterval(project.Config, opts.interval, defaultMonitorInterval)
+	p := newPlainMonitor(project.Config, interval, history)
+	p.all = opts.all || opts.once
+	checker := health.Checker{Deadline: checkDeadline(interval)}
+
+	for {
+		now := time.Now()
+		due := dueChecks(p.schedules, now)
+		if opts.once {
+			due = allChecks(p.checks)
+		}
+
+		results, ok := p.round(ctx, checker, os.Stdout, due, now)
+		if !ok {
+			// Interrupted mid-round: the results are cancellations, not outages
+			if opts.once {
+				return fmt.Errorf("interrupted")
+			}
+			return nil
+		}
+
+		for j, result := range results {
+			if event := observeResult(dispatcher, p.services[due[j]], result.Error); event != nil {
+				go func() {
+					if err := dispatcher.Send(ctx, *event); err != nil {
+						fmt.Fprintf(os.Stderr, "%s notification failed: %v\n", time.Now().Format(plainTimeFormat), err)
+					}
+				}()
+			}
+		}
+
+		if opts.record && len(results) > 0 {
+			// History is best-effort: a failed write shouldn't interrupt monitoring
+			_ = history.Append(project.HealthHistoryPath())
+		}
+
+		if opts.once {
+			report := buildStatusReport(filepath.Base(project.Root), p.services, results)
+			if !report.Healthy {
+				return fmt.Errorf("%d required service(s) down", len(downServices(report)))
+			}
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(tickInterval(p.schedules, interval)):
+		}
+	}
+}
+
+// plainMonitor is the state monitor --plain keeps between rounds
+type plainMonitor struct {
+	services  []ServiceHealth
+	checks    []health.Check
+	schedules []checkSchedule
+	history   *health.History
+	all       bool // Print every check, not only changes
+}
+
+// newPlainMonitor returns plain mode's state for a project, with every check due
+func newPlainMonitor(cfg *config.ProjectConfig, interval time.Duration, history *health.History) *plainMonitor {
+	services, checks := stackChecks(cfg)
+	return &plainMonitor{
+		services:  services,
+		checks:    checks,
+		schedules: stackSchedules(cfg, services, interval),
+		history:   history,
+	}
+}
+
+// round runs the checks at the due indexes, printing changes (or every result with all)
+// to w and recording them. The results are in due order; ok is false, with nothing
+// recorded, when ctx ended during the round
+func (p *plainMonitor) round(ctx context.Context, checker health.Checker, w io.Writer, due []int, now time.Time) ([]health.Result, bool) {
+	if len(due) == 0 {
+		return nil, true
+	}
+
+	round := make([]health.Check, len(due))
+	for j, i := range due {
+		round[j] = p.checks[i]
+	}
+	results := checker.Run(ctx, round)
+	if ctx.Err() != nil {
+		return nil, false
+	}
+
+	for j, result := range results {
+		i := due[j]
+		previous := p.services[i]
+		p.services[i].Status = result.Status
+
+		if result.Status != previous.Status || p.all {
+			printPlainLine(w, now, p.services[i], result, previous, p.history.Stats(previous.Name))
+		}
+
+		p.history.Record(health.Sample{Time: now, Service: p.services[i].Name, Status: result.Status, Latency: result.Latency})
+		p.schedules[i].record(result.Status, now)
+	}
+	return results, true
+}
+
+// printPlainLine writes a service's result as one line, e.g.
+// "2024-05-01 10:00:00  my-api  running  12ms  (was down for 1m3s)"
+func printPlainLine(w io.Writer, now time.Time, svc ServiceHealth, result health.Result, previous ServiceHealth, stats health.Stats) {
+	fields := []string{
+		now.Format(plainTimeFormat),
+		fmt.Sprintf("%-25s", svc.Name),
+		fmt.Sprintf("%-8s", result.Status),
+	}
+
+	if result.Status != health.StatusDown && result.Latency > 0 {
+		fields = append(fields, health.FormatLatency(result.Latency))
+	}
+	if result.Detail != "" {
+		fields = append(fields, result.Detail)
+	}
+	if result.Error != nil && result.Status != health.StatusRunning {
+		fields = append(fields, result.Error.Error())
+	}
+	if previous.Status != "" && previous.Status != result.Status {
+		fields = append(fields, fmt.Sprintf("(was %s for %s)", previous.Status, now.Sub(stats.Since).Round(time.Second)))
+	}
+
+	fmt.Fprintln(w, strings.Join(fields, "  "))
+}
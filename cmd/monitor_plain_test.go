@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stevengregory/musing-cli/internal/health"
+)
+
+// TestPrintPlainLine tests the timestamped line format, including recoveries
+func TestPrintPlainLine(t *testing.T) {
+	now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.Local)
+	svc := ServiceHealth{Name: "my-api", Status: health.StatusRunning}
+
+	tests := []struct {
+		name     string
+		result   health.Result
+		previous ServiceHealth
+		stats    health.Stats
+		expected []string
+	}{
+		{
+			name:     "first check",
+			result:   health.Result{Status: health.StatusRunning, Latency: 12 * time.Millisecond},
+			expected: []string{"2024-05-01 10:00:00", "my-api", "running", "12.0ms"},
+		},
+		{
+			name:     "recovered",
+			result:   health.Result{Status: health.StatusRunning, Latency: 12 * time.Millisecond},
+			previous: ServiceHealth{Name: "my-api", Status: health.StatusDown},
+			stats:    health.Stats{Since: now.Add(-63 * time.Second)},
+			expected: []string{"running", "(was down for 1m3s)"},
+		},
+		{
+			name:     "down",
+			result:   health.Result{Status: health.StatusDown, Latency: time.Second, Error: errors.New("connection refused")},
+			expected: []string{"down", "connection refused"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			printPlainLine(&buf, now, svc, tt.result, tt.previous, tt.stats)
+			line := buf.String()
+			for _, expected := range tt.expected {
+				if !strings.Contains(line, expected) {
+					t.Errorf("line %q doesn't contain %q", line, expected)
+				}
+			}
+			if tt.result.Status == health.StatusDown && strings.Contains(line, "1s") {
+				t.Errorf("line %q shows the latency of a failed check", line)
+			}
+		})
+	}
+}
+
+// TestPlainMonitorRound tests plain rounds run only due checks, follow their schedules,
+// print changes, and record nothing when interrupted
+func TestPlainMonitorRound(t *testing.T) {
+	status := func(s string) func(context.Context) health.Result {
+		return func(ctx context.Context) health.Result { return health.Result{Status: s} }
+	}
+	p := &plainMonitor{
+		services: []ServiceHealth{{Name: "my-api"}, {Name: "web"}},
+		checks: []health.Check{
+			{Name: "my-api", Run: status(health.StatusDown)},
+			{Name: "web", Run: status(health.StatusRunning)},
+		},
+		schedules: []checkSchedule{{interval: time.Second}, {interval: 10 * time.Second}},
+		history:   health.NewHistory(10),
+	}
+
+	now := time.Now()
+	var out bytes.Buffer
+	if _, ok := p.round(context.Background(), health.Checker{}, &out, dueChecks(p.schedules, now), now); !ok {
+		t.Fatal("round reported an interrupt")
+	}
+	if lines := strings.Count(out.String(), "\n"); lines != 2 {
+		t.Errorf("printed %d line(s) for the first round, expected 2:\n%s", lines, out.String())
+	}
+
+	// Only my-api is due again after its shorter interval, and nothing changed
+	out.Reset()
+	now = now.Add(time.Second)
+	due := dueChecks(p.schedules, now)
+	if len(due) != 1 || due[0] != 0 {
+		t.Fatalf("due = %v, expected only my-api", due)
+	}
+	p.round(context.Background(), health.Checker{}, &out, due, now)
+	if out.Len() != 0 {
+		t.Errorf("printed %q without a change", out.String())
+	}
+
+	// my-api stays down, so it backs off like it does in the dashboard
+	for range backoffAfter {
+		now = p.schedules[0].next
+		p.round(context.Background(), health.Checker{}, &out, []int{0}, now)
+	}
+	if !p.schedules[0].backingOff() {
+		t.Error("expected my-api to back off after staying down")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	samples := p.history.Stats("web").Samples
+	if _, ok := p.round(ctx, health.Checker{}, &out, []int{1}, now); ok {
+		t.Error("expected an interrupted round to report it")
+	}
+	if got := p.history.Stats("web").Samples; got != samples {
+		t.Errorf("interrupted round recorded %d sample(s)", got-samples)
+	}
+}
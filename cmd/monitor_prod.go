@@ -0,0 +1,345 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stevengregory/musing-cli/internal/config"
+	"github.com/stevengregory/musing-cli/internal/docker"
+	"github.com/stevengregory/musing-cli/internal/health"
+)
+
+// Production checks
+const (
+	defaultProdInterval = 15 * time.Second
+	prodTimeout         = 10 * time.Second // Limit for one round over SSH
+	prodSideBySideWidth = 120              // Narrower terminals show production below dev
+)
+
+// SectionProduction is the section production services are shown in
+const SectionProduction = "Production"
+
+// prodView is the production server, checked over SSH and shown beside the dev stack
+type prodView struct {
+	enabled  bool
+	server   string
+	services []ServiceHealth // The database, then each configured service
+	dbPort   int             // Local end of the SSH forward to the database (0: none)
+	interval time.Duration
+	checking bool
+	next     time.Time
+	err      error // Why the last round couldn't reach the server
+}
+
+// prodProbe is what the remote script reports about the server
+type prodProbe struct {
+	containers []docker.Container
+	ports      map[int]bool // Whether each port accepts connections on the server
+	composeErr string       // Why docker compose ps failed on the server
+}
+
+// prodCheckedMsg delivers a round of production checks
+type prodCheckedMsg struct {
+	probe prodProbe
+	db    health.Result // Database handshake through the forward (empty without one)
+	err   error
+}
+
+// newProdView returns the production view for a project, ready for its first round
+func newProdView(cfg *config.ProjectConfig) prodView {
+	services := []ServiceHealth{{
+		Name:    cfg.Database.Type,
+		Port:    remoteDBPort(cfg),
+		Kind:    KindDatabase,
+		Compose: cfg.Database.Compose,
+		Section: SectionProduction,
+	}}
+	for _, svc := range cfg.Services {
+		services = append(services, ServiceHealth{
+			Name:    svc.Name,
+			Port:    svc.ProductionPort(),
+			Kind:    svc.Type,
+			Compose: svc.ComposeService(),
+			Section: SectionProduction,
+		})
+	}
+
+	interval := cfg.Production.Interval
+	if interval <= 0 {
+		interval = defaultProdInterval
+	}
+	return prodView{
+		enabled:  true,
+		server:   cfg.Production.Server,
+		services: services,
+		interval: interval,
+		checking: true, // Init runs the first round
+	}
+}
+
+// prodSSHArgs returns ssh arguments for the production server. Connections are
+// multiplexed, so rounds reuse the database forward's connection instead of
+// logging in each time, and never prompt, which would hang the dashboard
+func prodSSHArgs(cfg *config.ProjectConfig, extra ...string) []string {
+	var args []string
+	if cfg.Production.SSHKeyPath != "" {
+		args = append(args, "-i", expandHomeDir(cfg.Production.SSHKeyPath))
+	}
+	args = append(args,
+		"-o", "BatchMode=yes",
+		"-o", "ConnectTimeout=5",
+		"-o", "ControlMaster=auto",
+		"-o", "ControlPath="+filepath.Join(os.TempDir(), "musing-ssh-%C"),
+	)
+	args = append(args, extra...)
+	return append(args, cfg.Production.Server)
+}
+
+// startProdForward forwards a free local port to the production database over SSH,
+// so it gets the same protocol-aware check as the dev database
+func startProdForward(cfg *config.ProjectConfig) (*exec.Cmd, int, error) {
+	port, err := freePort()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	cmd := exec.Command("ssh", prodSSHArgs(cfg,
+		"-N",
+		"-o", "ExitOnForwardFailure=yes",
+		"-o", "ServerAliveInterval=15",
+		"-L", fmt.Sprintf("127.0.0.1:%d:localhost:%d", port, remoteDBPort(cfg)),
+	)...)
+	if err := cmd.Start(); err != nil {
+		return nil, 0, fmt.Errorf("failed to start ssh: %w", err)
+	}
+	return cmd, port, nil
+}
+
+// freePort returns a local port nothing is listening on
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+// prodScript returns the bash script run on the server: docker compose ps, then
+// whether each port accepts connections, in lines parseProdOutput understands
+func prodScript(prod *config.ProductionConfig, ports []int) string {
+	compose := "docker compose"
+	if prod.ComposeProject != "" {
+		compose += " -p " + shellQuote(prod.ComposeProject)
+	}
+	if prod.ComposeDir != "" {
+		compose = "cd " + shellQuote(prod.ComposeDir) + " && " + compose
+	}
+
+	var b strings.Builder
+	b.WriteString("echo ::containers\n")
+	fmt.Fprintf(&b, "if out=$(%s ps --all --format json 2>&1); then printf '%%s\\n' \"$out\"; else echo \"::error $(printf '%%s\\n' \"$out\" | tail -n 1)\"; fi\n", compose)
+
+	seen := map[int]bool{}
+	for _, port := range ports {
+		if port == 0 || seen[port] {
+			continue
+		}
+		seen[port] = true
+		fmt.Fprintf(&b, "if timeout 2 bash -c '</dev/tcp/127.0.0.1/%d' 2>/dev/null; then echo '::port %d open'; else echo '::port %d closed'; fi\n", port, port, port)
+	}
+	return b.String()
+}
+
+// parseProdOutput parses the output of prodScript
+func parseProdOutput(output []byte) prodProbe {
+	probe := prodProbe{ports: map[int]bool{}}
+
+	var containers []byte
+	inContainers := false
+	for _, line := range strings.Split(string(output), "\n") {
+		switch {
+		case line == "::containers":
+			inContainers = true
+		case strings.HasPrefix(line, "::error "):
+			probe.composeErr = strings.TrimPrefix(line, "::error ")
+			inContainers = false
+		case strings.HasPrefix(line, "::port "):
+			inContainers = false
+			var port int
+			var state string
+			if _, err := fmt.Sscanf(line, "::port %d %s", &port, &state); err == nil {
+				probe.ports[port] = state == "open"
+			}
+		case inContainers:
+			containers = append(containers, line...)
+			containers = append(containers, '\n')
+		}
+	}
+
+	parsed, err := docker.ParseComposePS(containers)
+	if err != nil {
+		probe.composeErr = err.Error()
+	}
+	probe.containers = parsed
+	return probe
+}
+
+// shellQuote quotes a string for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// checkCmd runs a round of production checks: the remote script over SSH, and the
+// database handshake through the forward
+func (p prodView) checkCmd(cfg *config.ProjectConfig) tea.Cmd {
+	ports := make([]int, len(p.services))
+	for i, svc := range p.services {
+		ports[i] = svc.Port
+	}
+	script, dbPort := prodScript(cfg.Production, ports), p.dbPort
+
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), prodTimeout)
+		defer cancel()
+
+		var msg prodCheckedMsg
+		if dbPort != 0 {
+			msg.db = health.DatabaseCheck(cfg.Database.Type, cfg.Database.Type, dbPort).Run(ctx)
+		}
+
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, "ssh", append(prodSSHArgs(cfg), "bash", "-s")...)
+		cmd.Stdin = strings.NewReader(script)
+		cmd.Stderr = &stderr
+		output, err := cmd.Output()
+		if err != nil {
+			if line := lastLine(stderr.String()); line != "" {
+				err = fmt.Errorf("%s", line)
+			}
+			msg.err = fmt.Errorf("ssh %s failed: %w", cfg.Production.Server, err)
+			return msg
+		}
+
+		msg.probe = parseProdOutput(output)
+		return msg
+	}
+}
+
+// due reports whether a round should start
+func (p prodView) due(now time.Time) bool {
+	return p.enabled && !p.checking && !now.Before(p.next)
+}
+
+// apply records a round: services are down when their port is closed or their
+// container isn't running, and unknown when the server couldn't be reached
+func (p *prodView) apply(msg prodCheckedMsg, now time.Time) {
+	p.checking = false
+	p.next = now.Add(p.interval)
+	p.err = msg.err
+
+	containers := map[string]docker.Container{}
+	for _, c := range msg.probe.containers {
+		containers[c.Service] = c
+	}
+
+	for i := range p.services {
+		svc := &p.services[i]
+		svc.CheckedAt = now
+		svc.Status, svc.Detail, svc.Error, svc.Latency = "", "", "", 0
+		if msg.err != nil {
+			continue
+		}
+
+		// A forward that isn't connected yet shouldn't hide a database the server can reach
+		open, probed := msg.probe.ports[svc.Port]
+		forwarded := msg.db.Status != "" && (msg.db.Status != health.StatusDown || !open)
+
+		switch {
+		case svc.Kind == KindDatabase && forwarded:
+			svc.Status, svc.Detail = msg.db.Status, msg.db.Detail
+			if msg.db.Status != health.StatusDown {
+				svc.Latency = msg.db.Latency
+			}
+			if msg.db.Error != nil && msg.db.Status != health.StatusRunning {
+				svc.Error = msg.db.Error.Error()
+			}
+		case probed && open:
+			svc.Status = health.StatusRunning
+		case probed:
+			svc.Status = health.StatusDown
+			svc.Error = fmt.Sprintf("port %d closed on server", svc.Port)
+		}
+
+		c, ok := containers[svc.Compose]
+		if svc.Compose == "" || !ok {
+			continue
+		}
+		switch {
+		case c.State != "running":
+			svc.Status = health.StatusDown
+			svc.Error = "container " + c.State
+		case c.Health == "unhealthy":
+			svc.Status = health.StatusDegraded
+			svc.Detail = strings.TrimSpace(svc.Detail + " container unhealthy")
+		case svc.Status == "":
+			svc.Status = health.StatusRunning // No port to check
+		}
+	}
+
+	if msg.err == nil && msg.probe.composeErr != "" {
+		p.err = fmt.Errorf("docker compose ps on %s: %s", p.server, msg.probe.composeErr)
+	}
+}
+
+// render renders production as a titled section, like a dev section
+func (p prodView) render(thresholds latencyThresholds) string {
+	var b strings.Builder
+	b.WriteString(titleStyle.Render(fmt.Sprintf("Production • %s", p.server)))
+	b.WriteString("\n")
+
+	group := sectionGroup{MonitorSectionConfig: config.MonitorSectionConfig{Name: SectionProduction}, Services: p.services}
+	b.WriteString(sectionHeaderStyle.Render(group.title()))
+	b.WriteString("\n")
+
+	switch {
+	case p.err != nil:
+		b.WriteString(statusDownStyle.Render(p.err.Error()) + "\n")
+	case p.checking && p.next.IsZero():
+		b.WriteString(detailStyle.Render("Connecting to "+p.server+"...") + "\n")
+	}
+	b.WriteString(renderServiceList(p.services, -1, thresholds))
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// renderBody lays out the dev sections and, with --prod, production beside them (or
+// below them on narrow terminals), returning the body and the cursor's line in it
+func (m monitorModel) renderBody(width int) (string, int) {
+	if !m.prod.enabled {
+		return layoutBlocks(m.sectionBlocks(), width)
+	}
+
+	prod := m.prod.render(m.thresholds)
+	if width < prodSideBySideWidth {
+		body, cursorLine := layoutBlocks(m.sectionBlocks(), width)
+		return body + "\n\n" + prod, cursorLine
+	}
+
+	half := (width - columnGap) / 2
+	dev, cursorLine := layoutBlocks(m.sectionBlocks(), half)
+	if cursorLine >= 0 {
+		cursorLine++ // Below the column title
+	}
+	left := lipgloss.NewStyle().Width(half + columnGap).Render(truncateLines(titleStyle.Render("Development")+"\n"+dev, half))
+	right := truncateLines(prod, width-half-columnGap)
+	return lipgloss.JoinHorizontal(lipgloss.Top, left, right), cursorLine
+}
@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stevengregory/musing-cli/internal/config"
+	"github.com/stevengregory/musing-cli/internal/health"
+)
+
+// TestProdScript tests the remote script quotes its settings and checks each port once
+func TestProdScript(t *testing.T) {
+	script := prodScript(&config.ProductionConfig{ComposeDir: "/srv/my app", ComposeProject: "it's"}, []int{27017, 8080, 0, 8080})
+
+	if !strings.Contains(script, `cd '/srv/my app' && docker compose -p 'it'\''s' ps --all --format json`) {
+		t.Errorf("script doesn't run docker compose ps in the quoted directory:\n%s", script)
+	}
+	if n := strings.Count(script, "/dev/tcp/127.0.0.1/8080"); n != 1 {
+		t.Errorf("port 8080 checked %d times, expected once", n)
+	}
+	if strings.Contains(script, "/dev/tcp/127.0.0.1/0") {
+		t.Error("expected services without a port to be skipped")
+	}
+}
+
+// TestProdApply tests how the remote script's output and the database check set production statuses
+func TestProdApply(t *testing.T) {
+	cfg := &config.ProjectConfig{
+		Database: config.DatabaseConfig{Type: "mongodb", Compose: "mongo"},
+		Services: []config.ServiceConfig{
+			{Name: "my-api", Port: 8080, Type: "api"},
+			{Name: "web", Port: 3000, ProdPort: 80, Type: "frontend"},
+			{Name: "worker", Type: "worker"},
+		},
+		Production: &config.ProductionConfig{Server: "root@example.com"},
+	}
+	output := "::containers\n" +
+		`{"Name":"p-api-1","Service":"my-api","State":"running","Health":"unhealthy"}` + "\n" +
+		`{"Name":"p-worker-1","Service":"worker","State":"exited","ExitCode":1}` + "\n" +
+		`{"Name":"p-mongo-1","Service":"mongo","State":"running"}` + "\n" +
+		"::port 27017 open\n::port 8080 open\n::port 80 closed\n::port 3000 open\n"
+
+	p := newProdView(cfg)
+	p.apply(prodCheckedMsg{
+		probe: parseProdOutput([]byte(output)),
+		db:    health.Result{Status: health.StatusRunning, Detail: "v7.0.5", Latency: 3 * time.Millisecond},
+	}, time.Now())
+
+	expected := map[string]string{
+		"mongodb": health.StatusRunning,
+		"my-api":  health.StatusDegraded,
+		"web":     health.StatusDown,
+		"worker":  health.StatusDown,
+	}
+	for _, svc := range p.services {
+		if svc.Status != expected[svc.Name] {
+			t.Errorf("%s = %s (%s), expected %s", svc.Name, svc.Status, svc.Error, expected[svc.Name])
+		}
+	}
+	if db := p.services[0]; db.Detail != "v7.0.5" || db.Latency != 3*time.Millisecond {
+		t.Errorf("database = %+v, expected the handshake's detail and latency", db)
+	}
+	if p.err != nil || p.checking || p.next.IsZero() {
+		t.Errorf("after a round: err = %v, checking = %v, next = %v", p.err, p.checking, p.next)
+	}
+
+	// An unreachable server leaves every service unknown
+	p.apply(prodCheckedMsg{err: errors.New("ssh root@example.com failed: timeout")}, time.Now())
+	for _, svc := range p.services {
+		if svc.Status != "" {
+			t.Errorf("%s = %s after ssh failed, expected unknown", svc.Name, svc.Status)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/stevengregory/musing-cli/internal/config"
+	"github.com/stevengregory/musing-cli/internal/health"
+)
+
+// Check intervals
+const (
+	defaultMonitorInterval  = 3 * time.Second
+	defaultExporterInterval = 10 * time.Second
+	backoffAfter            = 3           // Consecutive down checks before a service is checked less often
+	maxBackoff              = time.Minute // Longest interval a down service backs off to
+)
+
+// checkSchedule tracks when a service is next due for a health check
+type checkSchedule struct {
+	interval time.Duration // Interval while healthy
+	downs    int           // Consecutive down results
+	next     time.Time     // Zero until the first check
+}
+
+// checkInterval returns flag (the --interval value) when set, else the monitor config's
+// interval, or fallback
+func checkInterval(cfg *config.ProjectConfig, flag, fallback time.Duration) time.Duration {
+	switch {
+	case flag > 0:
+		return flag
+	case cfg.Monitor.Interval > 0:
+		return cfg.Monitor.Interval
+	default:
+		return fallback
+	}
+}
+
+// stackSchedules returns a schedule for each of the stack's checks, in stackChecks order.
+// Services with their own interval use it; everything else uses the global interval
+func stackSchedules(cfg *config.ProjectConfig, services []ServiceHealth, interval time.Duration) []checkSchedule {
+	intervals := map[string]time.Duration{}
+	for _, svc := range cfg.Services {
+		if svc.Interval > 0 {
+			intervals[svc.Name] = svc.Interval
+		}
+	}
+
+	schedules := make([]checkSchedule, len(services))
+	for i, svc := range services {
+		schedules[i].interval = interval
+		if configured, ok := intervals[svc.Name]; ok && svc.Kind != KindDocker && svc.Kind != KindDatabase && svc.Kind != KindTunnel {
+			schedules[i].interval = configured
+		}
+	}
+	return schedules
+}
+
+// record schedules the next check after a result
+func (s *checkSchedule) record(status string, now time.Time) {
+	if status == health.StatusDown {
+		s.downs++
+	} else {
+		s.downs = 0
+	}
+	s.next = now.Add(s.delay())
+}
+
+// delay is the time until the next check: the interval, doubling for each down
+// result past backoffAfter up to maxBackoff
+func (s checkSchedule) delay() time.Duration {
+	delay := s.interval
+	for i := backoffAfter; i < s.downs && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, max(maxBackoff, s.interval))
+}
+
+// backingOff reports whether the service is being checked less often because it's been down
+func (s checkSchedule) backingOff() bool {
+	return s.delay() > s.interval
+}
+
+// dueChecks returns the indexes of schedules whose next check is due
+func dueChecks(schedules []checkSchedule, now time.Time) []int {
+	var due []int
+	for i, s := range schedules {
+		if !now.Before(s.next) {
+			due = append(due, i)
+		}
+	}
+	return due
+}
+
+// tickInterval is how often the monitor looks for due checks: the shortest interval in use
+func tickInterval(schedules []checkSchedule, interval time.Duration) time.Duration {
+	tick := interval
+	for _, s := range schedules {
+		tick = min(tick, s.interval)
+	}
+	return tick
+}
+
+// checkDeadline bounds a round of checks so it finishes before the next tick. Remote
+// checks use their own timeout instead, so slow endpoints aren't reported as timed out
+func checkDeadline(interval time.Duration) time.Duration {
+	return max(min(interval-500*time.Millisecond, 5*time.Second), time.Second)
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stevengregory/musing-cli/internal/config"
+	"github.com/stevengregory/musing-cli/internal/health"
+)
+
+// TestCheckScheduleBackoff tests that long-down services are checked less often, up to maxBackoff
+func TestCheckScheduleBackoff(t *testing.T) {
+	s := checkSchedule{interval: 3 * time.Second}
+	now := time.Now()
+
+	expected := []time.Duration{3, 3, 3, 6, 12, 24, 48, 60, 60}
+	for i, seconds := range expected {
+		s.record(health.StatusDown, now)
+		if delay := s.delay(); delay != seconds*time.Second {
+			t.Errorf("after %d down checks: delay %s, expected %ds", i+1, delay, seconds)
+		}
+	}
+
+	s.record(health.StatusRunning, now)
+	if s.backingOff() || !s.next.Equal(now.Add(3*time.Second)) {
+		t.Errorf("after recovering: %+v, expected the normal interval", s)
+	}
+}
+
+// TestCheckInterval tests --interval wins over the monitor config, which wins over the default
+func TestCheckInterval(t *testing.T) {
+	configured := &config.ProjectConfig{Monitor: config.MonitorConfig{Interval: 7 * time.Second}}
+
+	tests := []struct {
+		name     string
+		cfg      *config.ProjectConfig
+		flag     time.Duration
+		expected time.Duration
+	}{
+		{name: "default", cfg: &config.ProjectConfig{}, expected: defaultMonitorInterval},
+		{name: "config", cfg: configured, expected: 7 * time.Second},
+		{name: "flag", cfg: configured, flag: time.Second, expected: time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkInterval(tt.cfg, tt.flag, defaultMonitorInterval); got != tt.expected {
+				t.Errorf("checkInterval() = %s, expected %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestStackSchedules tests per-service intervals and picking due checks
+func TestStackSchedules(t *testing.T) {
+	cfg := &config.ProjectConfig{
+		Services: []config.ServiceConfig{
+			{Name: "news-api", Port: 8080, Type: "api", Interval: time.Second},
+			{Name: "web", Port: 3000, Type: "frontend"},
+		},
+	}
+	services, _ := stackChecks(cfg)
+	schedules := stackSchedules(cfg, services, 5*time.Second)
+
+	now := time.Now()
+	for i := range schedules {
+		schedules[i].record(health.StatusRunning, now)
+	}
+
+	if tick := tickInterval(schedules, 5*time.Second); tick != time.Second {
+		t.Errorf("tickInterval() = %s, expected the shortest service interval", tick)
+	}
+
+	due := dueChecks(schedules, now.Add(2*time.Second))
+	if len(due) != 1 || services[due[0]].Name != "news-api" {
+		t.Errorf("due after 2s = %v, expected only news-api", due)
+	}
+	if due := dueChecks(schedules, now.Add(5*time.Second)); len(due) != len(services) {
+		t.Errorf("%d checks due after 5s, expected all %d", len(due), len(services))
+	}
+}
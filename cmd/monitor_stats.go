@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stevengregory/musing-cli/internal/docker"
+)
+
+// statsHistorySize is the number of samples shown in resource sparklines
+const statsHistorySize = 20
+
+// sparkBars are the sparkline levels, lowest first
+var sparkBars = []rune("▁▂▃▄▅▆▇█")
+
+// containerStatus is a compose service's container state and recent resource usage
+type containerStatus struct {
+	docker.Stats
+	State  string    // running, exited, restarting...
+	CPU    []float64 // Recent CPU %, oldest first
+	Memory []float64 // Recent memory usage in bytes, oldest first
+}
+
+// containerStatsMsg delivers a sample of the project's containers
+type containerStatsMsg struct {
+	containers []docker.Container
+	stats      map[string]docker.Stats // Keyed by container name
+	err        error
+}
+
+// containerStatsCmd samples state and resource usage for the project's containers
+func containerStatsCmd(compose docker.Compose) tea.Cmd {
+	return func() tea.Msg {
+		containers, err := docker.ComposePS(compose)
+		if err != nil {
+			return containerStatsMsg{err: err}
+		}
+
+		var running []string
+		for _, c := range containers {
+			if c.State == "running" {
+				running = append(running, c.Name)
+			}
+		}
+		stats, err := docker.ContainerStats(running)
+		return containerStatsMsg{containers: containers, stats: stats, err: err}
+	}
+}
+
+// applyContainerStats records a sample, keyed by compose service. Container states are
+// applied even when resource usage couldn't be sampled, keeping the last usage of
+// containers still running
+func (m *monitorModel) applyContainerStats(msg containerStatsMsg) {
+	if msg.containers == nil && msg.err != nil {
+		return // Keep the last sample; stats are informational
+	}
+
+	statuses := map[string]containerStatus{}
+	for _, c := range msg.containers {
+		status := m.containers[c.Service]
+		if msg.err != nil && c.State == "running" && status.State == "running" {
+			status.Health = c.Health
+			statuses[c.Service] = status
+			continue
+		}
+		status.State = c.State
+		status.Stats = docker.Stats{Health: c.Health}
+
+		if stats, ok := msg.stats[c.Name]; ok {
+			status.Stats = stats
+			status.CPU = appendSample(status.CPU, stats.CPUPercent)
+			status.Memory = appendSample(status.Memory, float64(stats.MemUsage))
+		}
+		statuses[c.Service] = status
+	}
+	m.containers = statuses
+}
+
+// appendSample appends a value, keeping the last statsHistorySize
+func appendSample(samples []float64, value float64) []float64 {
+	samples = append(slices.Clone(samples), value)
+	return samples[max(0, len(samples)-statsHistorySize):]
+}
+
+// formatContainer summarises a container: CPU and memory with sparklines, uptime, restarts and health
+func formatContainer(c *containerStatus) string {
+	if c.State != "running" {
+		return statusDownStyle.Render("container " + c.State)
+	}
+
+	// CPU is scaled from zero so idle containers show flat; memory to its own range so growth stands out
+	cpuHigh := 1.0
+	for _, v := range c.CPU {
+		cpuHigh = max(cpuHigh, v)
+	}
+	parts := []string{
+		detailStyle.Render(fmt.Sprintf("cpu %5.1f%% %s", c.CPUPercent, sparkline(c.CPU, 0, cpuHigh))),
+	}
+
+	memory := "mem " + docker.FormatBytes(c.MemUsage)
+	if c.MemLimit > 0 {
+		memory += "/" + docker.FormatBytes(c.MemLimit)
+	}
+	if len(c.Memory) > 0 {
+		memory += " " + sparkline(c.Memory, slices.Min(c.Memory), slices.Max(c.Memory))
+	}
+	parts = append(parts, detailStyle.Render(memory))
+
+	if !c.StartedAt.IsZero() {
+		parts = append(parts, detailStyle.Render("up "+formatUptime(time.Since(c.StartedAt))))
+	}
+	if c.Restarts > 0 {
+		parts = append(parts, statusDegradedStyle.Render(fmt.Sprintf("%d restart(s)", c.Restarts)))
+	}
+
+	switch c.Health {
+	case "healthy":
+		parts = append(parts, statusRunningStyle.Render("healthy"))
+	case "unhealthy":
+		parts = append(parts, statusDownStyle.Render("unhealthy"))
+	case "starting":
+		parts = append(parts, statusDegradedStyle.Render("starting"))
+	}
+
+	return strings.Join(parts, detailStyle.Render(" • "))
+}
+
+// sparkline draws values scaled between lo and hi
+func sparkline(values []float64, lo, hi float64) string {
+	var b strings.Builder
+	for _, v := range values {
+		level := 0
+		if hi > lo {
+			level = int((v - lo) / (hi - lo) * float64(len(sparkBars)-1))
+		}
+		b.WriteRune(sparkBars[max(0, min(level, len(sparkBars)-1))])
+	}
+	return b.String()
+}
+
+// formatUptime formats a duration to its two largest units, e.g. "3h12m"
+func formatUptime(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+	}
+}
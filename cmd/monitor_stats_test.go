@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stevengregory/musing-cli/internal/docker"
+)
+
+// TestApplyContainerStats tests mapping container samples to compose services with bounded history
+func TestApplyContainerStats(t *testing.T) {
+	var m monitorModel
+	msg := containerStatsMsg{
+		containers: []docker.Container{
+			{Name: "proj-api-1", Service: "api", State: "running"},
+			{Name: "proj-worker-1", Service: "worker", State: "exited"},
+		},
+		stats: map[string]docker.Stats{
+			"proj-api-1": {Name: "proj-api-1", CPUPercent: 12.5, MemUsage: 100},
+		},
+	}
+
+	for range statsHistorySize + 5 {
+		m.applyContainerStats(msg)
+	}
+
+	api := m.containers["api"]
+	if api.State != "running" || api.CPUPercent != 12.5 || len(api.CPU) != statsHistorySize || len(api.Memory) != statsHistorySize {
+		t.Errorf("api = %+v, expected running with %d samples", api, statsHistorySize)
+	}
+	if worker := m.containers["worker"]; worker.State != "exited" || len(worker.CPU) != 0 {
+		t.Errorf("worker = %+v, expected exited without samples", worker)
+	}
+
+	// A failed stats call still applies container states from compose ps
+	m.applyContainerStats(containerStatsMsg{
+		containers: []docker.Container{
+			{Name: "proj-api-1", Service: "api", State: "exited"},
+			{Name: "proj-worker-1", Service: "worker", State: "running"},
+		},
+		err: errors.New("docker stats failed"),
+	})
+	if api := m.containers["api"]; api.State != "exited" || api.CPUPercent != 0 {
+		t.Errorf("api = %+v, expected exited without its last usage", api)
+	}
+	if worker := m.containers["worker"]; worker.State != "running" {
+		t.Errorf("worker = %+v, expected running", worker)
+	}
+}
+
+// TestSparkline tests scaling values onto sparkline bars
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		values   []float64
+		lo, hi   float64
+		expected string
+	}{
+		{values: []float64{0, 50, 100}, lo: 0, hi: 100, expected: "▁▄█"},
+		{values: []float64{5, 5}, lo: 5, hi: 5, expected: "▁▁"},
+		{values: nil, lo: 0, hi: 1, expected: ""},
+	}
+
+	for _, tt := range tests {
+		if got := sparkline(tt.values, tt.lo, tt.hi); got != tt.expected {
+			t.Errorf("sparkline(%v, %v, %v) = %q, expected %q", tt.values, tt.lo, tt.hi, got, tt.expected)
+		}
+	}
+}
@@ -1,32 +1,170 @@
 package cmd
 
-import "testing"
-
-// TestGetStatus tests the getStatus function
-func TestGetStatus(t *testing.T) {
-	tests := []struct {
-		name     string
-		open     bool
-		expected string
-	}{
-		{
-			name:     "service is running",
-			open:     true,
-			expected: "running",
-		},
-		{
-			name:     "service is down",
-			open:     false,
-			expected: "down",
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stevengregory/musing-cli/internal/config"
+	"github.com/stevengregory/musing-cli/internal/health"
+)
+
+// TestMonitorSections tests grouping services by type and by configured sections
+func TestMonitorSections(t *testing.T) {
+	cfg := &config.ProjectConfig{
+		Services: []config.ServiceConfig{
+			{Name: "web", Port: 3000, Type: "frontend"},
+			{Name: "news-api", Port: 8080, Type: "api"},
+			{Name: "auth-api", Port: 8081, Type: "api"},
+			{Name: "mailer", Port: 2525, Type: "worker"},
+			{Name: "cache", Port: 6379},
 		},
+		Database: config.DatabaseConfig{Type: "MongoDB", DevPort: 27018, ProdPort: 27019},
+		Monitor: config.MonitorConfig{Sections: []config.MonitorSectionConfig{
+			{Name: "Auth", Icon: "🔑", Services: []string{"auth-api"}},
+			{Name: "Workers", Types: []string{"worker"}},
+		}},
+	}
+
+	m := initialMonitorModel(cfg, 0)
+	m.services, _ = stackChecks(cfg)
+
+	expected := map[string][]string{
+		"Auth":          {"auth-api"},
+		"Workers":       {"mailer"},
+		SectionDocker:   {ServiceDockerDesktop},
+		SectionDatabase: {"MongoDB"},
+		SectionAPI:      {"news-api"},
+		SectionFrontend: {"web"},
+		SectionServices: {"cache"},
+		SectionTunnels:  {"Production"},
+	}
+	order := []string{"Auth", "Workers", SectionDocker, SectionDatabase, SectionAPI, SectionFrontend, SectionServices, SectionTunnels}
+
+	groups := m.groups()
+	if len(groups) != len(order) {
+		t.Fatalf("got %d sections, expected %d", len(groups), len(order))
+	}
+	for i, group := range groups {
+		if group.Name != order[i] {
+			t.Errorf("section %d = %q, expected %q", i, group.Name, order[i])
+		}
+		var names []string
+		for _, svc := range group.Services {
+			names = append(names, svc.Name)
+		}
+		if fmt.Sprint(names) != fmt.Sprint(expected[group.Name]) {
+			t.Errorf("%s section = %v, expected %v", group.Name, names, expected[group.Name])
+		}
+	}
+
+	if title := groups[0].title(); title != "━━━ 🔑 Auth (0/1) ━━━" {
+		t.Errorf("title() = %q", title)
+	}
+}
+
+// TestCollapseSection tests that a collapsed section is a single selectable row
+func TestCollapseSection(t *testing.T) {
+	m := initialMonitorModel(&config.ProjectConfig{}, 0)
+	m.services = []ServiceHealth{
+		{Name: "news-api", Section: SectionAPI},
+		{Name: "auth-api", Section: SectionAPI},
+		{Name: "web", Section: SectionFrontend},
+	}
+
+	m.cursor = 1
+	m.toggleSection()
+	if rows := m.rows(); len(rows) != 2 || m.cursor != 0 {
+		t.Fatalf("after collapsing: %d rows, cursor %d; expected 2 rows, cursor 0", len(rows), m.cursor)
+	}
+	if _, ok := m.selected(); ok {
+		t.Error("a collapsed section shouldn't select a service")
+	}
+
+	m.toggleSection()
+	if rows := m.rows(); len(rows) != 3 {
+		t.Errorf("after expanding: %d rows, expected 3", len(rows))
+	}
+}
+
+// TestMonitorSelection tests cursor movement in display order and confirmation of destructive actions
+func TestMonitorSelection(t *testing.T) {
+	cfg := &config.ProjectConfig{
+		Database: config.DatabaseConfig{Type: "MongoDB", DevPort: 27018, ProdPort: 27019},
+	}
+
+	m := initialMonitorModel(cfg, 0)
+	m.services = []ServiceHealth{
+		{Name: ServiceDockerDesktop, Section: SectionDocker},
+		{Name: "MongoDB", Port: 27018, Section: SectionDatabase},
+		{Name: "news-api", Port: 8080, Section: SectionAPI, Compose: "news"},
+		{Name: "Production", Port: 27019, Section: SectionTunnels},
+	}
+
+	press := func(key string) {
+		model, _ := m.handleKey(key)
+		m = model.(monitorModel)
+	}
+
+	press("down")
+	press("down")
+	press("down")
+	press("down") // Stops at the last service
+	if svc, _ := m.selected(); svc.Name != "Production" {
+		t.Errorf("selected %q after moving down, want %q", svc.Name, "Production")
+	}
+
+	press("up")
+	if svc, _ := m.selected(); svc.Name != "news-api" {
+		t.Fatalf("selected %q after moving up, want %q", svc.Name, "news-api")
+	}
+
+	press("R")
+	if m.confirm == nil || m.confirm.action.command != "restart" {
+		t.Fatalf("restart should wait for confirmation, got %+v", m.confirm)
+	}
+	press("n")
+	if m.confirm != nil {
+		t.Error("any key other than y should cancel the confirmation")
+	}
+
+	press("up")
+	press("s")
+	if m.confirm != nil || !m.statusErr {
+		t.Error("stop should be refused for a service without a container")
+	}
+
+	if url := serviceURL(ServiceHealth{Port: 8080, Kind: "api"}); url != "http://localhost:8080" {
+		t.Errorf("serviceURL() = %q, want %q", url, "http://localhost:8080")
+	}
+}
+
+// TestApplyResult tests that check latency, time and error reach the displayed service
+func TestApplyResult(t *testing.T) {
+	m := initialMonitorModel(&config.ProjectConfig{}, 0)
+	m.services = []ServiceHealth{{Name: "news-api", Port: 8080}}
+
+	m.applyResult(0, health.Result{Status: health.StatusRunning, Latency: 12 * time.Millisecond})
+	if svc := m.services[0]; svc.Latency != 12*time.Millisecond || svc.Error != "" || svc.CheckedAt.IsZero() {
+		t.Errorf("after a healthy check: %+v", svc)
+	}
+
+	m.applyResult(0, health.Result{Status: health.StatusDown, Latency: time.Second, Error: errors.New("connection refused")})
+	if svc := m.services[0]; svc.Latency != 0 || svc.Error != "connection refused" {
+		t.Errorf("after a failed check: latency %v, error %q", svc.Latency, svc.Error)
+	}
+}
+
+// TestThresholdsFor tests latency threshold defaults and overrides
+func TestThresholdsFor(t *testing.T) {
+	defaults := thresholdsFor(&config.ProjectConfig{})
+	if defaults.warn != 250*time.Millisecond || defaults.critical != time.Second {
+		t.Errorf("default thresholds = %+v", defaults)
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := getStatus(tt.open)
-			if result != tt.expected {
-				t.Errorf("getStatus(%v) = %q, want %q", tt.open, result, tt.expected)
-			}
-		})
+	configured := thresholdsFor(&config.ProjectConfig{Monitor: config.MonitorConfig{LatencyWarn: 50 * time.Millisecond}})
+	if configured.warn != 50*time.Millisecond || configured.critical != time.Second {
+		t.Errorf("configured thresholds = %+v", configured)
 	}
 }
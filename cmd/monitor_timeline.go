@@ -0,0 +1,294 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/stevengregory/musing-cli/internal/docker"
+	"github.com/stevengregory/musing-cli/internal/health"
+	"github.com/stevengregory/musing-cli/internal/timeline"
+)
+
+// timelinePane lists recent events, newest first
+type timelinePane struct {
+	open      bool
+	scroll    int // Events scrolled past from the newest
+	maximized bool
+}
+
+// containerEventMsg delivers an event from the project's docker compose events stream
+type containerEventMsg struct {
+	event  docker.ComposeEvent
+	events <-chan docker.ComposeEvent
+}
+
+// waitForContainerEvent waits for the next container event (nil without a stream)
+func waitForContainerEvent(events <-chan docker.ComposeEvent) tea.Cmd {
+	if events == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		event, ok := <-events
+		if !ok {
+			return nil // Docker stopped streaming; the rest of the timeline still works
+		}
+		return containerEventMsg{event: event, events: events}
+	}
+}
+
+// transitionEvent describes a service changing status, with how long the previous status
+// lasted. Tunnel transitions are reported as drops and restores
+func transitionEvent(svc ServiceHealth, status string, now time.Time) (timeline.Event, bool) {
+	if svc.Status == "" || svc.Status == status {
+		return timeline.Event{}, false // First check, or no change
+	}
+
+	event := timeline.Event{
+		Time:    now,
+		Kind:    timeline.KindStatus,
+		Service: svc.Name,
+		Message: svc.Status + " → " + status,
+		Level:   eventLevel(status),
+	}
+	if svc.Stats.Samples > 0 {
+		event.Duration = now.Sub(svc.Stats.Since)
+	}
+
+	if svc.Kind == KindTunnel {
+		event.Kind = timeline.KindTunnel
+		switch {
+		case status == health.StatusDown:
+			event.Message = "tunnel dropped"
+		case svc.Status == health.StatusDown:
+			event.Message = "tunnel restored"
+		}
+	}
+	return event, true
+}
+
+// eventLevel returns the timeline level for a new service status
+func eventLevel(status string) string {
+	switch status {
+	case health.StatusRunning:
+		return timeline.LevelOK
+	case health.StatusDegraded:
+		return timeline.LevelWarn
+	default:
+		return timeline.LevelError
+	}
+}
+
+// containerEvent turns a docker event into a timeline event, skipping routine ones
+// (create, attach, exec...). exited tracks when each service's container last exited,
+// so a start reports how long it was down
+func containerEvent(event docker.ComposeEvent, name string, exited map[string]time.Time) (timeline.Event, bool) {
+	e := timeline.Event{Time: event.Time, Kind: timeline.KindContainer, Service: name}
+
+	switch event.Action {
+	case "die":
+		exited[event.Service] = event.Time
+		e.Message, e.Level = "container exited", timeline.LevelError
+		if code := event.Attributes["exitCode"]; code != "" {
+			e.Message += " (code " + code + ")"
+			if code == "0" {
+				e.Level = timeline.LevelInfo
+			}
+		}
+	case "start":
+		e.Message, e.Level = "container started", timeline.LevelOK
+		if at, ok := exited[event.Service]; ok {
+			e.Duration = event.Time.Sub(at)
+			delete(exited, event.Service)
+		}
+	case "restart":
+		e.Message, e.Level = "container restarted", timeline.LevelWarn
+	case "oom":
+		e.Message, e.Level = "container ran out of memory", timeline.LevelError
+	case "health_status: healthy":
+		e.Message, e.Level = "container healthy", timeline.LevelOK
+	case "health_status: unhealthy":
+		e.Message, e.Level = "container unhealthy", timeline.LevelWarn
+	default:
+		return timeline.Event{}, false
+	}
+	return e, true
+}
+
+// addEvents adds events to the timeline, recording them with --record
+func (m *monitorModel) addEvents(events ...timeline.Event) {
+	m.showEvents(events...)
+	if m.recordEvents && m.timelinePath != "" {
+		// The timeline is best-effort: a failed write shouldn't interrupt monitoring
+		_ = timeline.Append(m.timelinePath, events...)
+	}
+}
+
+// showEvents adds events to the timeline, keeping the pane still when scrolled
+func (m *monitorModel) showEvents(events ...timeline.Event) {
+	m.timeline.Add(events...)
+	if m.timelinePane.scroll > 0 {
+		m.timelinePane.scroll = min(m.timelinePane.scroll+len(events), max(m.timeline.Len()-1, 0))
+	}
+}
+
+// tailTimeline picks up deploys other musing commands have recorded since the last read
+func (m *monitorModel) tailTimeline() {
+	if m.timelinePath == "" {
+		return
+	}
+	events, offset, err := timeline.ReadFrom(m.timelinePath, m.timelineOffset)
+	if err != nil {
+		return // Try again next tick
+	}
+	m.timelineOffset = offset
+
+	var deploys []timeline.Event
+	for _, event := range events {
+		if event.Kind == timeline.KindDeploy {
+			deploys = append(deploys, event) // Anything else was written by a monitor
+		}
+	}
+	if len(deploys) > 0 {
+		m.showEvents(deploys...)
+	}
+}
+
+// composeName returns the name a compose service is shown under in the monitor
+func (m monitorModel) composeName(service string) string {
+	for _, svc := range m.services {
+		if svc.Compose == service {
+			return svc.Name
+		}
+	}
+	return service
+}
+
+// exportTimeline writes the timeline to a timestamped text file in dir, returning its path
+func exportTimeline(events []timeline.Event, dir string, now time.Time) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, "timeline-"+now.Format("20060102-150405")+".log")
+
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	if err := timeline.Export(file, events); err != nil {
+		file.Close()
+		return "", err
+	}
+	return path, file.Close()
+}
+
+// handleTimelineKey handles a key while the timeline pane has focus
+func (m monitorModel) handleTimelineKey(key string) (tea.Model, tea.Cmd) {
+	last := max(m.timeline.Len()-1, 0)
+	page := m.pageSize()
+
+	switch key {
+	case "q", "ctrl+c":
+		return m, tea.Quit
+	case "tab":
+		m.focusPane = false
+	case "esc", "e":
+		m.timelinePane.open = false
+		m.focusPane = false
+	case "up", "k":
+		m.timelinePane.scroll = max(m.timelinePane.scroll-1, 0)
+	case "down", "j":
+		m.timelinePane.scroll = min(m.timelinePane.scroll+1, last)
+	case "pgup", "ctrl+u":
+		m.timelinePane.scroll = max(m.timelinePane.scroll-page, 0)
+	case "pgdown", "ctrl+d":
+		m.timelinePane.scroll = min(m.timelinePane.scroll+page, last)
+	case "home", "g":
+		m.timelinePane.scroll = 0
+	case "end", "G":
+		m.timelinePane.scroll = last
+	case "z":
+		m.timelinePane.maximized = !m.timelinePane.maximized
+	case "x":
+		path, err := exportTimeline(m.timeline.Events(), m.exportDir, time.Now())
+		if err != nil {
+			m.setStatus(fmt.Sprintf("Failed to export timeline: %v", err), true)
+		} else {
+			m.setStatus(fmt.Sprintf("Exported %d event(s) to %s", m.timeline.Len(), path), false)
+		}
+	}
+	return m, nil
+}
+
+// view renders the pane in a box of the given outer size
+func (p timelinePane) view(events []timeline.Event, width, height int, focused bool, now time.Time) string {
+	// Border and padding take 4 columns and 2 rows, the title one more row
+	innerWidth := max(width-4, 20)
+	rows := max(height-3, 1)
+
+	var b strings.Builder
+	b.WriteString(p.header(len(events), focused))
+
+	shown := 0
+	for i := len(events) - 1 - p.scroll; i >= 0 && shown < rows; i-- {
+		b.WriteString("\n")
+		b.WriteString(ansi.Truncate(renderEvent(events[i], now), innerWidth, "…"))
+		shown++
+	}
+	if len(events) == 0 {
+		b.WriteString("\n" + detailStyle.Render("No events yet"))
+		shown++
+	}
+	for range rows - shown {
+		b.WriteString("\n")
+	}
+
+	return logPaneStyle.Width(innerWidth + 2).Render(b.String())
+}
+
+// header describes what the pane is showing
+func (p timelinePane) header(count int, focused bool) string {
+	title := "Timeline"
+	if focused {
+		title = "▸ " + title
+	}
+
+	state := []string{fmt.Sprintf("%d event(s)", count)}
+	if p.scroll > 0 {
+		state = append(state, fmt.Sprintf("scrolled %d", p.scroll))
+	} else {
+		state = append(state, "newest first")
+	}
+	return logTitleStyle.Render(title) + " " + detailStyle.Render(strings.Join(state, " • "))
+}
+
+// renderEvent renders one timeline line: time, a dot coloured by level, service, message and duration
+func renderEvent(event timeline.Event, now time.Time) string {
+	at := event.Time.Local()
+	stamp := at.Format("15:04:05")
+	if y, m, d := now.Local().Date(); at.Year() != y || at.Month() != m || at.Day() != d {
+		stamp = at.Format("Jan 02 15:04")
+	}
+
+	var icon string
+	switch event.Level {
+	case timeline.LevelOK:
+		icon = statusRunningStyle.Render("●")
+	case timeline.LevelWarn:
+		icon = statusDegradedStyle.Render("●")
+	case timeline.LevelError:
+		icon = statusDownStyle.Render("●")
+	default:
+		icon = detailStyle.Render("●")
+	}
+
+	line := fmt.Sprintf("%s %s %-25s %s", detailStyle.Render(fmt.Sprintf("%-12s", stamp)), icon, event.Service, event.Message)
+	if d := timeline.FormatDuration(event); d != "" {
+		line += " " + detailStyle.Render(d)
+	}
+	return line
+}
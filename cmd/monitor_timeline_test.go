@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stevengregory/musing-cli/internal/config"
+	"github.com/stevengregory/musing-cli/internal/docker"
+	"github.com/stevengregory/musing-cli/internal/health"
+	"github.com/stevengregory/musing-cli/internal/timeline"
+)
+
+// TestTransitionEvent tests which status changes make timeline events
+func TestTransitionEvent(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	since := health.Stats{Samples: 5, Since: now.Add(-time.Hour)}
+
+	tests := []struct {
+		name     string
+		svc      ServiceHealth
+		status   string
+		expected *timeline.Event
+	}{
+		{
+			name:   "first check",
+			svc:    ServiceHealth{Name: "my-api"},
+			status: health.StatusRunning,
+		},
+		{
+			name:   "unchanged",
+			svc:    ServiceHealth{Name: "my-api", Status: health.StatusRunning},
+			status: health.StatusRunning,
+		},
+		{
+			name:     "service down",
+			svc:      ServiceHealth{Name: "my-api", Status: health.StatusRunning, Stats: since},
+			status:   health.StatusDown,
+			expected: &timeline.Event{Time: now, Kind: timeline.KindStatus, Service: "my-api", Message: "running → down", Level: timeline.LevelError, Duration: time.Hour},
+		},
+		{
+			name:     "tunnel dropped",
+			svc:      ServiceHealth{Name: "MongoDB Tunnel", Kind: KindTunnel, Status: health.StatusRunning},
+			status:   health.StatusDown,
+			expected: &timeline.Event{Time: now, Kind: timeline.KindTunnel, Service: "MongoDB Tunnel", Message: "tunnel dropped", Level: timeline.LevelError},
+		},
+		{
+			name:     "tunnel restored",
+			svc:      ServiceHealth{Name: "MongoDB Tunnel", Kind: KindTunnel, Status: health.StatusDown, Stats: since},
+			status:   health.StatusRunning,
+			expected: &timeline.Event{Time: now, Kind: timeline.KindTunnel, Service: "MongoDB Tunnel", Message: "tunnel restored", Level: timeline.LevelOK, Duration: time.Hour},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, ok := transitionEvent(tt.svc, tt.status, now)
+			if tt.expected == nil {
+				if ok {
+					t.Errorf("got event %+v, expected none", event)
+				}
+				return
+			}
+			if !ok || event != *tt.expected {
+				t.Errorf("got %+v, expected %+v", event, *tt.expected)
+			}
+		})
+	}
+}
+
+// TestContainerEvent tests docker events are summarised and a restart reports its downtime
+func TestContainerEvent(t *testing.T) {
+	at := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	exited := map[string]time.Time{}
+
+	if _, ok := containerEvent(docker.ComposeEvent{Time: at, Service: "api", Action: "create"}, "my-api", exited); ok {
+		t.Error("expected create to be skipped")
+	}
+
+	died, ok := containerEvent(docker.ComposeEvent{Time: at, Service: "api", Action: "die", Attributes: map[string]string{"exitCode": "137"}}, "my-api", exited)
+	if !ok || died.Message != "container exited (code 137)" || died.Level != timeline.LevelError || died.Service != "my-api" {
+		t.Errorf("die = %+v", died)
+	}
+
+	started, ok := containerEvent(docker.ComposeEvent{Time: at.Add(12 * time.Second), Service: "api", Action: "start"}, "my-api", exited)
+	if !ok || started.Duration != 12*time.Second {
+		t.Errorf("start = %+v, expected 12s after the exit", started)
+	}
+	if _, ok := exited["api"]; ok {
+		t.Error("expected the exit to be forgotten once started")
+	}
+}
+
+// TestTimelinePane tests opening, scrolling and exporting the timeline
+func TestTimelinePane(t *testing.T) {
+	m := initialMonitorModel(&config.ProjectConfig{}, 0)
+	m.exportDir = t.TempDir()
+
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	for i := range 3 {
+		m.addEvents(timeline.Event{Time: start.Add(time.Duration(i) * time.Minute), Kind: timeline.KindStatus, Service: "my-api", Message: "running → down"})
+	}
+
+	model, _ := m.handleKey("e")
+	m = model.(monitorModel)
+	if !m.timelinePane.open || !m.focusPane {
+		t.Fatal("expected e to open and focus the timeline")
+	}
+
+	model, _ = m.handleKey("down")
+	m = model.(monitorModel)
+	m.addEvents(timeline.Event{Time: start.Add(time.Hour), Message: "new"})
+	if m.timelinePane.scroll != 2 {
+		t.Errorf("scroll = %d after a new event while scrolled, want 2", m.timelinePane.scroll)
+	}
+
+	model, _ = m.handleKey("x")
+	m = model.(monitorModel)
+	path, found := strings.CutPrefix(m.statusLine, "Exported 4 event(s) to ")
+	if !found {
+		t.Fatalf("status line = %q", m.statusLine)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Count(string(data), "\n"); lines != 4 {
+		t.Errorf("exported %d line(s), expected 4", lines)
+	}
+
+	model, _ = m.handleKey("esc")
+	m = model.(monitorModel)
+	if m.timelinePane.open {
+		t.Error("expected esc to close the timeline")
+	}
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stevengregory/musing-cli/internal/config"
+	"github.com/stevengregory/musing-cli/internal/notify"
+)
+
+// newDispatcher builds the project's notification dispatcher, or returns nil when
+// no notifiers are configured
+func newDispatcher(project *config.Project) (*notify.Dispatcher, error) {
+	cfg := project.Config.Notify
+	if cfg == nil || len(cfg.Notifiers) == 0 {
+		return nil, nil
+	}
+
+	dispatcher := &notify.Dispatcher{
+		Project:  filepath.Base(project.Root),
+		Debounce: cfg.Debounce,
+	}
+	for i, nc := range cfg.Notifiers {
+		notifier, err := notifierFor(nc)
+		if err != nil {
+			return nil, fmt.Errorf("notify.notifiers[%d]: %w", i, err)
+		}
+		dispatcher.Targets = append(dispatcher.Targets, notify.Target{Notifier: notifier, Services: nc.Services})
+	}
+	return dispatcher, nil
+}
+
+// notifierFor builds a notifier from its config
+func notifierFor(nc config.NotifierConfig) (notify.Notifier, error) {
+	switch nc.Type {
+	case "command":
+		if nc.Command == "" {
+			return nil, fmt.Errorf("command notifier needs a command")
+		}
+		return notify.Command{Command: nc.Command}, nil
+	case "webhook":
+		if nc.URL == "" {
+			return nil, fmt.Errorf("webhook notifier needs a url")
+		}
+		webhook := notify.Webhook{URL: nc.URL, Headers: nc.Headers}
+		if nc.Body != "" {
+			body, err := notify.ParseBody(nc.Body)
+			if err != nil {
+				return nil, fmt.Errorf("invalid webhook body template: %w", err)
+			}
+			webhook.Body = body
+		}
+		return webhook, nil
+	case "slack":
+		if nc.URL == "" {
+			return nil, fmt.Errorf("slack notifier needs a url")
+		}
+		return notify.Slack{URL: nc.URL}, nil
+	default:
+		return nil, fmt.Errorf("unknown notifier type %q (expected command, webhook or slack)", nc.Type)
+	}
+}
+
+// notifySentMsg reports the outcome of sending a notification
+type notifySentMsg struct {
+	event notify.Event
+	err   error
+}
+
+// sendNotificationCmd delivers a notification without blocking the UI
+func sendNotificationCmd(dispatcher *notify.Dispatcher, event notify.Event) tea.Cmd {
+	return func() tea.Msg {
+		return notifySentMsg{event: event, err: dispatcher.Send(context.Background(), event)}
+	}
+}
+
+// observeResult feeds a check result to the dispatcher, if any, returning a
+// transition event ready to send
+func observeResult(dispatcher *notify.Dispatcher, svc ServiceHealth, err error) *notify.Event {
+	if dispatcher == nil {
+		return nil
+	}
+	return dispatcher.Observe(svc.Name, svc.Section, svc.Status, err, time.Now())
+}
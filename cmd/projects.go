@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+	"github.com/stevengregory/musing-cli/internal/config"
+	"github.com/stevengregory/musing-cli/internal/ui"
+)
+
+var projectsCmd = &cobra.Command{
+	Use:   "projects",
+	Short: "Manage named projects",
+	Long:  `Register projects by name so musing can be run from anywhere with --project <name>.`,
+	Annotations: map[string]string{
+		projectAnnotation: projectOptional,
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Default to list
+		return projectsList()
+	},
+}
+
+var projectsAddCmd = &cobra.Command{
+	Use:   "add <name> [dir]",
+	Short: "Register a project",
+	Long:  `Register a project directory under a name. Defaults to the current project.`,
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := ""
+		if len(args) > 1 {
+			dir = args[1]
+		}
+		return projectsAdd(projectFrom(cmd), args[0], dir)
+	},
+}
+
+var projectsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List registered projects",
+	Long:  `List registered projects, marking the one selected with 'musing projects use'.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return projectsList()
+	},
+}
+
+var projectsUseCmd = &cobra.Command{
+	Use:               "use <name>",
+	Short:             "Select the default project",
+	Long:              `Select the project musing uses when run outside of any project directory.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProjectNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return projectsUpdate(args[0], (*config.Registry).Use, "Now using project '%s'")
+	},
+}
+
+var projectsRemoveCmd = &cobra.Command{
+	Use:               "remove <name>",
+	Short:             "Unregister a project",
+	Long:              `Remove a project from the registry. The project directory is left untouched.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeProjectNames,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return projectsUpdate(args[0], (*config.Registry).Remove, "Removed project '%s'")
+	},
+}
+
+func init() {
+	projectsCmd.AddCommand(projectsAddCmd)
+	projectsCmd.AddCommand(projectsListCmd)
+	projectsCmd.AddCommand(projectsUseCmd)
+	projectsCmd.AddCommand(projectsRemoveCmd)
+}
+
+func projectsAdd(project *config.Project, name, dir string) error {
+	if dir == "" {
+		if project == nil {
+			ui.Error("Could not find project root")
+			ui.Info("Run this command from inside a project, or pass the project directory")
+			return fmt.Errorf("no project directory given")
+		}
+		dir = project.Root
+	}
+
+	registry, err := config.LoadRegistry()
+	if err != nil {
+		ui.Error(err.Error())
+		return err
+	}
+	if err := registry.Add(name, dir); err != nil {
+		ui.Error(err.Error())
+		return err
+	}
+	if err := registry.Save(); err != nil {
+		ui.Error(fmt.Sprintf("Failed to save project registry: %v", err))
+		return err
+	}
+
+	ui.Success(fmt.Sprintf("Registered project '%s' (%s)", name, registry.Projects[name]))
+	return nil
+}
+
+func projectsList() error {
+	registry, err := config.LoadRegistry()
+	if err != nil {
+		ui.Error(err.Error())
+		return err
+	}
+
+	if len(registry.Projects) == 0 {
+		ui.Info("No projects registered")
+		ui.Info("Use 'musing projects add <name>' inside a project to register it")
+		return nil
+	}
+
+	currentStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("10")).Bold(true)
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	fmt.Println()
+	for _, name := range registry.Names() {
+		marker := " "
+		label := fmt.Sprintf("%-20s", name)
+		if name == registry.Current {
+			marker = currentStyle.Render("*")
+			label = currentStyle.Render(label)
+		}
+		fmt.Printf("%s %s %s\n", marker, label, dimStyle.Render(registry.Projects[name]))
+	}
+	fmt.Println()
+
+	return nil
+}
+
+// projectsUpdate applies a registry change for name, saves it, and reports success
+func projectsUpdate(name string, update func(*config.Registry, string) error, successMsg string) error {
+	registry, err := config.LoadRegistry()
+	if err != nil {
+		ui.Error(err.Error())
+		return err
+	}
+	if err := update(registry, name); err != nil {
+		ui.Error(err.Error())
+		return err
+	}
+	if err := registry.Save(); err != nil {
+		ui.Error(fmt.Sprintf("Failed to save project registry: %v", err))
+		return err
+	}
+
+	ui.Success(fmt.Sprintf(successMsg, name))
+	return nil
+}
+
+// completeProjectNames completes registered project names
+func completeProjectNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	registry, err := config.LoadRegistry()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return registry.Names(), cobra.ShellCompDirectiveNoFileComp
+}
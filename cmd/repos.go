@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/spf13/cobra"
+	"github.com/stevengregory/musing-cli/internal/config"
+	"github.com/stevengregory/musing-cli/internal/git"
+	"github.com/stevengregory/musing-cli/internal/ui"
+)
+
+var reposCmd = &cobra.Command{
+	Use:   "repos",
+	Short: "Manage service repositories",
+	Long:  `Show status of, clone, and pull the git repositories backing the configured services.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Default to status
+		return reposStatus(projectFrom(cmd), false)
+	},
+}
+
+var reposStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show repository status",
+	Long:  `Show branch, uncommitted changes, and ahead/behind state for every service repository.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fetch, _ := cmd.Flags().GetBool("fetch")
+		return reposStatus(projectFrom(cmd), fetch)
+	},
+}
+
+var reposCloneCmd = &cobra.Command{
+	Use:   "clone",
+	Short: "Clone missing repositories",
+	Long:  `Clone every service repository that does not exist yet, using the repo url configured in .musing.yaml.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return reposClone(projectFrom(cmd))
+	},
+}
+
+var reposPullCmd = &cobra.Command{
+	Use:   "pull",
+	Short: "Pull all repositories",
+	Long:  `Fast-forward every service repository from its upstream, in parallel.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return reposPull(projectFrom(cmd))
+	},
+}
+
+func init() {
+	reposStatusCmd.Flags().Bool("fetch", false, "Fetch from remotes before computing ahead/behind")
+
+	reposCmd.AddCommand(reposStatusCmd)
+	reposCmd.AddCommand(reposCloneCmd)
+	reposCmd.AddCommand(reposPullCmd)
+}
+
+// repoStatusResult holds the outcome of inspecting a single repo
+type repoStatusResult struct {
+	repo    config.Repo
+	missing bool
+	status  git.Status
+	err     error
+}
+
+func reposStatus(project *config.Project, fetch bool) error {
+	repos := project.Repos()
+	if len(repos) == 0 {
+		ui.Info("No repositories configured in .musing.yaml")
+		return nil
+	}
+
+	results := make([]repoStatusResult, len(repos))
+	forEachRepo(repos, func(i int, repo config.Repo) {
+		result := repoStatusResult{repo: repo}
+		if !git.IsRepo(repo.Path) {
+			result.missing = true
+			results[i] = result
+			return
+		}
+		if fetch {
+			if err := git.Fetch(repo.Path); err != nil {
+				result.err = err
+			}
+		}
+		if result.err == nil {
+			result.status, result.err = git.GetStatus(repo.Path)
+		}
+		results[i] = result
+	})
+
+	headerStyle := lipgloss.NewStyle().
+		Foreground(lipgloss.Color("99")).
+		Bold(true).
+		Border(lipgloss.RoundedBorder()).
+		Padding(0, 1)
+	successStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
+	warningStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("11"))
+	errorStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+	dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	fmt.Println()
+	fmt.Println(headerStyle.Render("Repository Status"))
+	fmt.Println()
+
+	missing := 0
+	for _, r := range results {
+		switch {
+		case r.missing:
+			missing++
+			fmt.Printf("%s %-25s %s\n", errorStyle.Render("●"), r.repo.Name, errorStyle.Render("missing"))
+		case r.err != nil:
+			fmt.Printf("%s %-25s %s\n", errorStyle.Render("●"), r.repo.Name, errorStyle.Render(r.err.Error()))
+		default:
+			icon := successStyle.Render("●")
+			state := describeRepoState(r.status)
+			if r.status.Dirty || r.status.Behind > 0 {
+				icon = warningStyle.Render("●")
+			}
+			fmt.Printf("%s %-25s %-20s %s\n", icon, r.repo.Name, r.status.Branch, state)
+		}
+		fmt.Println(dimStyle.Render("  " + r.repo.Path))
+	}
+
+	if missing > 0 {
+		fmt.Println()
+		fmt.Println(dimStyle.Render("  Run 'musing repos clone' to clone missing repositories"))
+	}
+	fmt.Println()
+
+	return nil
+}
+
+// describeRepoState summarises dirty and ahead/behind state for display
+func describeRepoState(status git.Status) string {
+	state := "clean"
+	if status.Dirty {
+		state = "dirty"
+	}
+
+	if !status.HasUpstream {
+		return state + ", no upstream"
+	}
+	if status.Ahead > 0 {
+		state += fmt.Sprintf(", ↑%d", status.Ahead)
+	}
+	if status.Behind > 0 {
+		state += fmt.Sprintf(", ↓%d", status.Behind)
+	}
+	if status.Ahead == 0 && status.Behind == 0 {
+		state += ", up to date"
+	}
+	return state
+}
+
+func reposClone(project *config.Project) error {
+	repos := project.Repos()
+
+	var failed []string
+	cloned := 0
+	for _, repo := range repos {
+		if git.IsRepo(repo.Path) {
+			continue
+		}
+
+		if repo.URL == "" {
+			ui.Warning(fmt.Sprintf("%s: no repo url configured, skipping", repo.Name))
+			failed = append(failed, repo.Name)
+			continue
+		}
+
+		if _, err := os.Stat(repo.Path); err == nil {
+			ui.Warning(fmt.Sprintf("%s: %s exists but is not a git repository, skipping", repo.Name, repo.Path))
+			failed = append(failed, repo.Name)
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(repo.Path), 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", filepath.Dir(repo.Path), err)
+		}
+
+		msg := fmt.Sprintf("Cloning %s...", repo.Name)
+		if err := ui.SpinWithBubbles(msg, "git", git.CloneArgs(repo.URL, repo.Path, repo.Branch)...); err != nil {
+			ui.Error(fmt.Sprintf("Failed to clone %s from %s", repo.Name, repo.URL))
+			failed = append(failed, repo.Name)
+			continue
+		}
+		cloned++
+	}
+
+	fmt.Println()
+	if cloned == 0 && len(failed) == 0 {
+		ui.Success("All repositories already present")
+		return nil
+	}
+	if cloned > 0 {
+		ui.Success(fmt.Sprintf("Cloned %d repositories", cloned))
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("could not clone %d repositories", len(failed))
+	}
+	return nil
+}
+
+func reposPull(project *config.Project) error {
+	repos := project.Repos()
+
+	type pullResult struct {
+		summary string
+		skipped bool
+		err     error
+	}
+
+	ui.Info(fmt.Sprintf("Pulling %d repositories...", len(repos)))
+	results := make([]pullResult, len(repos))
+	forEachRepo(repos, func(i int, repo config.Repo) {
+		if !git.IsRepo(repo.Path) {
+			results[i] = pullResult{skipped: true}
+			return
+		}
+		summary, err := git.Pull(repo.Path)
+		results[i] = pullResult{summary: summary, err: err}
+	})
+
+	fmt.Println()
+	failed := 0
+	for i, r := range results {
+		name := repos[i].Name
+		switch {
+		case r.skipped:
+			ui.Warning(fmt.Sprintf("%s: missing (run 'musing repos clone')", name))
+		case r.err != nil:
+			failed++
+			ui.Error(fmt.Sprintf("%s: %v", name, r.err))
+		default:
+			summary, _, _ := strings.Cut(r.summary, "\n")
+			ui.Success(fmt.Sprintf("%s: %s", name, summary))
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to pull %d repositories", failed)
+	}
+	return nil
+}
+
+// forEachRepo runs fn for every repo concurrently and waits for all to finish
+func forEachRepo(repos []config.Repo, fn func(i int, repo config.Repo)) {
+	var wg sync.WaitGroup
+	for i, repo := range repos {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fn(i, repo)
+		}()
+	}
+	wg.Wait()
+}
@@ -1,12 +1,15 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 
 	figure "github.com/common-nighthawk/go-figure"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/spf13/cobra"
+	"github.com/stevengregory/musing-cli/internal/config"
+	"github.com/stevengregory/musing-cli/internal/ui"
 )
 
 var (
@@ -36,7 +39,9 @@ var rootCmd = &cobra.Command{
 		if cmd.Name() != "musing" && shouldShowBanner(cmd) {
 			printBanner()
 		}
-		return nil
+
+		// Load the project once and hand it to the subcommand via its context
+		return loadProject(cmd)
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		// Show banner for root help
@@ -45,6 +50,17 @@ var rootCmd = &cobra.Command{
 	},
 }
 
+// projectAnnotation marks how a command (and its subcommands) uses the project config:
+// projectNone skips loading, projectOptional loads it when available
+const (
+	projectAnnotation = "musing/project"
+	projectNone       = "none"
+	projectOptional   = "optional"
+)
+
+// projectKey is the context key under which the loaded project is stored
+type projectKey struct{}
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
@@ -71,6 +87,11 @@ func init() {
 		originalHelpFunc(cmd, args)
 	})
 
+	// Global project selection flags
+	rootCmd.PersistentFlags().StringP("project", "p", "", "Project directory or registered project name (env: "+config.ProjectEnvVar+")")
+	rootCmd.PersistentFlags().String("config", "", "Path to the project config file (default: .musing.yaml found from the current directory)")
+	rootCmd.RegisterFlagCompletionFunc("project", completeProjectNames)
+
 	// Set command groups first
 	rootCmd.AddGroup(&cobra.Group{
 		ID:    "core",
@@ -85,12 +106,20 @@ func init() {
 	devCmd.GroupID = "core"
 	deployCmd.GroupID = "core"
 	monitorCmd.GroupID = "core"
+	statusCmd.GroupID = "core"
+	reposCmd.GroupID = "core"
+	secretsCmd.GroupID = "core"
+	projectsCmd.GroupID = "additional"
 	sshCmd.GroupID = "core"
 	tunnelCmd.GroupID = "core"
 
 	rootCmd.AddCommand(devCmd)
 	rootCmd.AddCommand(deployCmd)
 	rootCmd.AddCommand(monitorCmd)
+	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(reposCmd)
+	rootCmd.AddCommand(secretsCmd)
+	rootCmd.AddCommand(projectsCmd)
 	rootCmd.AddCommand(sshCmd)
 	rootCmd.AddCommand(tunnelCmd)
 
@@ -127,7 +156,7 @@ func shouldShowBanner(cmd *cobra.Command) bool {
 	current := cmd
 	for current != nil {
 		switch current.Name() {
-		case "monitor", "completion", "bash", "zsh", "fish", "powershell", "help", cobra.ShellCompRequestCmd:
+		case "monitor", "status", "secrets", "completion", "bash", "zsh", "fish", "powershell", "help", cobra.ShellCompRequestCmd:
 			return false
 		}
 		current = current.Parent()
@@ -135,3 +164,71 @@ func shouldShowBanner(cmd *cobra.Command) bool {
 
 	return true
 }
+
+// searchOptions builds project search options from the global flags
+func searchOptions(cmd *cobra.Command) config.SearchOptions {
+	project, _ := cmd.Flags().GetString("project")
+	configFile, _ := cmd.Flags().GetString("config")
+	return config.SearchOptions{
+		Project:    project,
+		ConfigFile: configFile,
+	}
+}
+
+// projectMode returns how cmd uses the project, inherited from the nearest annotated ancestor
+func projectMode(cmd *cobra.Command) string {
+	for current := cmd; current != nil; current = current.Parent() {
+		if !current.HasParent() {
+			break
+		}
+		switch current.Name() {
+		case "help", "completion", cobra.ShellCompRequestCmd:
+			return projectNone
+		}
+		if mode, ok := current.Annotations[projectAnnotation]; ok {
+			return mode
+		}
+	}
+
+	// The root command only prints help
+	if !cmd.HasParent() {
+		return projectNone
+	}
+	return ""
+}
+
+// loadProject loads the project for commands that need one and stores it in the command context
+func loadProject(cmd *cobra.Command) error {
+	mode := projectMode(cmd)
+	if mode == projectNone {
+		return nil
+	}
+
+	project, err := config.Load(searchOptions(cmd))
+	if err != nil {
+		if mode == projectOptional {
+			return nil
+		}
+		fmt.Println()
+		ui.Error("Could not find project root")
+		ui.Info(err.Error())
+		ui.Info("Run this command from inside a project with .musing.yaml, or pass --project")
+		return err
+	}
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	cmd.SetContext(context.WithValue(ctx, projectKey{}, project))
+	return nil
+}
+
+// projectFrom returns the project loaded for cmd, or nil if none was loaded
+func projectFrom(cmd *cobra.Command) *config.Project {
+	if cmd.Context() == nil {
+		return nil
+	}
+	project, _ := cmd.Context().Value(projectKey{}).(*config.Project)
+	return project
+}
@@ -0,0 +1,454 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/stevengregory/musing-cli/internal/config"
+	"github.com/stevengregory/musing-cli/internal/secrets"
+	"github.com/stevengregory/musing-cli/internal/ui"
+)
+
+// Environment variables that unlock secrets without prompting
+const (
+	secretsKeyFileEnvVar    = "MUSING_SECRETS_KEY_FILE"
+	secretsPassphraseEnvVar = "MUSING_SECRETS_PASSPHRASE"
+)
+
+var secretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "Manage encrypted secrets per environment",
+	Long: `Store API keys and other secrets in a locally encrypted file (.musing/secrets/<env>.enc),
+unlocked with a passphrase or a key file. 'musing dev start' writes dev secrets into the .env file
+compose reads.`,
+}
+
+var secretsSetCmd = &cobra.Command{
+	Use:   "set <name> [value]",
+	Short: "Set a secret",
+	Long:  `Set a secret. If value is omitted it is read from stdin, or prompted for without echo.`,
+	Args:  cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		env, _ := cmd.Flags().GetString("env")
+		value, err := secretValue(args)
+		if err != nil {
+			return err
+		}
+		return updateSecrets(projectFrom(cmd), env, func(values map[string]string) error {
+			values[args[0]] = value
+			return nil
+		}, fmt.Sprintf("Set %s (%s)", args[0], env))
+	},
+}
+
+var secretsUnsetCmd = &cobra.Command{
+	Use:   "unset <name>",
+	Short: "Remove a secret",
+	Long:  `Remove a secret from an environment.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		env, _ := cmd.Flags().GetString("env")
+		return updateSecrets(projectFrom(cmd), env, func(values map[string]string) error {
+			if _, ok := values[args[0]]; !ok {
+				return fmt.Errorf("secret %s is not set for %s", args[0], env)
+			}
+			delete(values, args[0])
+			return nil
+		}, fmt.Sprintf("Removed %s (%s)", args[0], env))
+	},
+}
+
+var secretsGetCmd = &cobra.Command{
+	Use:   "get <name>",
+	Short: "Print a secret value",
+	Long:  `Print a secret value to stdout, for use in scripts.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		env, _ := cmd.Flags().GetString("env")
+		values, err := loadSecrets(projectFrom(cmd), env)
+		if err != nil {
+			return err
+		}
+
+		value, ok := values[args[0]]
+		if !ok {
+			ui.Error(fmt.Sprintf("Secret %s is not set for %s", args[0], env))
+			return fmt.Errorf("secret not found")
+		}
+		fmt.Println(value)
+		return nil
+	},
+}
+
+var secretsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List secret names",
+	Long:  `List the names of all secrets in an environment.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		env, _ := cmd.Flags().GetString("env")
+		values, err := loadSecrets(projectFrom(cmd), env)
+		if err != nil {
+			return err
+		}
+
+		if len(values) == 0 {
+			ui.Info(fmt.Sprintf("No secrets set for %s", env))
+			return nil
+		}
+		for _, name := range secrets.Names(values) {
+			fmt.Println(name)
+		}
+		return nil
+	},
+}
+
+var secretsEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Edit secrets in $EDITOR",
+	Long:  `Decrypt an environment's secrets into a temporary NAME=value file, open it in $EDITOR, and re-encrypt on save.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		env, _ := cmd.Flags().GetString("env")
+		return editSecrets(projectFrom(cmd), env)
+	},
+}
+
+var secretsCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check compose variables are defined",
+	Long:  `List variables referenced by the compose files that are not defined by secrets, the .env file, or the environment.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		env, _ := cmd.Flags().GetString("env")
+		project := projectFrom(cmd)
+
+		values, err := loadSecrets(project, env)
+		if err != nil {
+			return err
+		}
+
+		missing, err := undefinedComposeVars(project, values)
+		if err != nil {
+			return err
+		}
+		if len(missing) > 0 {
+			reportUndefinedVars(missing)
+			return fmt.Errorf("%d undefined variables", len(missing))
+		}
+		ui.Success("All compose variables are defined")
+		return nil
+	},
+}
+
+var secretsKeygenCmd = &cobra.Command{
+	Use:   "keygen [path]",
+	Short: "Generate a key file",
+	Long:  `Generate a random key file to use instead of a passphrase. Defaults to secrets.keyFile from .musing.yaml.`,
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := projectFrom(cmd).SecretsKeyFile()
+		if len(args) > 0 {
+			path = args[0]
+		}
+		if path == "" {
+			ui.Error("No key file path given")
+			ui.Info("Pass a path, or set secrets.keyFile in .musing.yaml")
+			return fmt.Errorf("no key file path")
+		}
+
+		if err := secrets.GenerateKeyFile(path); err != nil {
+			ui.Error(fmt.Sprintf("Failed to generate key file: %v", err))
+			return err
+		}
+		ui.Success(fmt.Sprintf("Generated key file %s", path))
+		ui.Info("Keep it private and out of git; secrets encrypted with it cannot be recovered without it")
+		return nil
+	},
+}
+
+func init() {
+	secretsCmd.PersistentFlags().StringP("env", "e", "dev", "Environment: dev or prod")
+	secretsCmd.RegisterFlagCompletionFunc("env", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"dev", "prod"}, cobra.ShellCompDirectiveNoFileComp
+	})
+
+	secretsCmd.AddCommand(secretsSetCmd)
+	secretsCmd.AddCommand(secretsUnsetCmd)
+	secretsCmd.AddCommand(secretsGetCmd)
+	secretsCmd.AddCommand(secretsListCmd)
+	secretsCmd.AddCommand(secretsEditCmd)
+	secretsCmd.AddCommand(secretsCheckCmd)
+	secretsCmd.AddCommand(secretsKeygenCmd)
+}
+
+// secretsKey returns the key that unlocks secrets: a key file if configured, otherwise a
+// passphrase from MUSING_SECRETS_PASSPHRASE or an interactive prompt
+func secretsKey(project *config.Project, env string, isNew bool) (secrets.Key, error) {
+	keyFile := os.Getenv(secretsKeyFileEnvVar)
+	if keyFile == "" {
+		keyFile = project.SecretsKeyFile()
+	}
+	if keyFile != "" {
+		key, err := secrets.KeyFromFile(keyFile)
+		if err != nil {
+			ui.Error(fmt.Sprintf("Failed to read key file: %v", err))
+			ui.Info("Run 'musing secrets keygen' to create one")
+		}
+		return key, err
+	}
+
+	if passphrase := os.Getenv(secretsPassphraseEnvVar); passphrase != "" {
+		return secrets.KeyFromPassphrase(passphrase), nil
+	}
+
+	passphrase, err := ui.Password(fmt.Sprintf("Passphrase for %s secrets", env))
+	if err != nil {
+		return secrets.Key{}, fmt.Errorf("passphrase prompt cancelled")
+	}
+	if isNew {
+		confirm, err := ui.Password("Confirm passphrase")
+		if err != nil {
+			return secrets.Key{}, fmt.Errorf("passphrase prompt cancelled")
+		}
+		if confirm != passphrase {
+			ui.Error("Passphrases do not match")
+			return secrets.Key{}, fmt.Errorf("passphrase mismatch")
+		}
+	}
+	return secrets.KeyFromPassphrase(passphrase), nil
+}
+
+// loadSecrets unlocks and decrypts an environment's secrets. An environment without a
+// secrets file has none, so no key is asked for
+func loadSecrets(project *config.Project, env string) (map[string]string, error) {
+	path := project.SecretsPath(env)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+
+	key, err := secretsKey(project, env, false)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := secrets.Load(path, key)
+	if err != nil {
+		ui.Error(err.Error())
+		return nil, err
+	}
+	return values, nil
+}
+
+// updateSecrets decrypts an environment's secrets, applies update, and re-encrypts them
+func updateSecrets(project *config.Project, env string, update func(map[string]string) error, successMsg string) error {
+	path := project.SecretsPath(env)
+	_, statErr := os.Stat(path)
+	isNew := os.IsNotExist(statErr)
+
+	key, err := secretsKey(project, env, isNew)
+	if err != nil {
+		return err
+	}
+
+	values, err := secrets.Load(path, key)
+	if err != nil {
+		ui.Error(err.Error())
+		return err
+	}
+
+	if err := update(values); err != nil {
+		ui.Error(err.Error())
+		return err
+	}
+
+	if err := secrets.Save(path, key, values); err != nil {
+		ui.Error(fmt.Sprintf("Failed to save secrets: %v", err))
+		return err
+	}
+
+	ui.Success(successMsg)
+	return nil
+}
+
+// secretValue returns the value argument, or reads it from stdin or a prompt
+func secretValue(args []string) (string, error) {
+	if len(args) > 1 {
+		return args[1], nil
+	}
+
+	if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice == 0 {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+
+	value, err := ui.Password(fmt.Sprintf("Value for %s", args[0]))
+	if err != nil {
+		return "", fmt.Errorf("prompt cancelled")
+	}
+	return value, nil
+}
+
+// editSecrets round-trips secrets through a temporary NAME=value file in $EDITOR
+func editSecrets(project *config.Project, env string) error {
+	path := project.SecretsPath(env)
+	_, statErr := os.Stat(path)
+	isNew := os.IsNotExist(statErr)
+
+	key, err := secretsKey(project, env, isNew)
+	if err != nil {
+		return err
+	}
+	values, err := secrets.Load(path, key)
+	if err != nil {
+		ui.Error(err.Error())
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "musing-secrets-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	tmpFile := filepath.Join(tmpDir, env+".env")
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s secrets - one NAME=value per line, lines starting with # are ignored\n", env)
+	for _, name := range secrets.Names(values) {
+		fmt.Fprintf(&b, "%s=%s\n", name, values[name])
+	}
+	if err := os.WriteFile(tmpFile, []byte(b.String()), 0600); err != nil {
+		return err
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	editorArgs := strings.Fields(editor)
+	editCmd := exec.Command(editorArgs[0], append(editorArgs[1:], tmpFile)...)
+	editCmd.Stdin = os.Stdin
+	editCmd.Stdout = os.Stdout
+	editCmd.Stderr = os.Stderr
+	if err := editCmd.Run(); err != nil {
+		ui.Error(fmt.Sprintf("Editor exited with error, secrets unchanged: %v", err))
+		return err
+	}
+
+	edited, err := parseSecretsFile(tmpFile)
+	if err != nil {
+		ui.Error(err.Error())
+		return err
+	}
+
+	if err := secrets.Save(path, key, edited); err != nil {
+		ui.Error(fmt.Sprintf("Failed to save secrets: %v", err))
+		return err
+	}
+	ui.Success(fmt.Sprintf("Saved %d secrets (%s)", len(edited), env))
+	return nil
+}
+
+// parseSecretsFile reads NAME=value lines written by editSecrets
+func parseSecretsFile(path string) (map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	values := map[string]string{}
+	scanner := bufio.NewScanner(file)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		name, value, ok := strings.Cut(line, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("line %d: expected NAME=value", lineNum)
+		}
+		values[name] = value
+	}
+	return values, scanner.Err()
+}
+
+// materialiseSecrets writes an environment's secrets into the project's .env file and
+// warns about compose variables that are still undefined
+func materialiseSecrets(project *config.Project, env string) error {
+	values := map[string]string{}
+
+	if _, err := os.Stat(project.SecretsPath(env)); err == nil {
+		loaded, err := loadSecrets(project, env)
+		if err != nil {
+			return err
+		}
+		values = loaded
+
+		envFile := project.EnvFilePath()
+		if err := secrets.WriteEnvFile(envFile, env, values); err != nil {
+			ui.Error(fmt.Sprintf("Failed to write %s: %v", envFile, err))
+			return err
+		}
+		ui.Success(fmt.Sprintf("Wrote %d secrets to %s", len(values), filepath.Base(envFile)))
+	}
+
+	missing, err := undefinedComposeVars(project, values)
+	if err != nil {
+		ui.Warning(fmt.Sprintf("Could not check compose variables: %v", err))
+		return nil
+	}
+	if len(missing) > 0 {
+		reportUndefinedVars(missing)
+	}
+	return nil
+}
+
+// undefinedComposeVars returns compose variables without a default that are not defined
+// by secrets, the .env file, or the current environment
+func undefinedComposeVars(project *config.Project, values map[string]string) ([]string, error) {
+	required, err := secrets.RequiredComposeVars(project.ComposeFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	defined, err := secrets.ReadEnvNames(project.EnvFilePath())
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	for _, name := range required {
+		if _, ok := values[name]; ok {
+			continue
+		}
+		if _, ok := os.LookupEnv(name); ok || defined[name] {
+			continue
+		}
+		missing = append(missing, name)
+	}
+	return missing, nil
+}
+
+// reportUndefinedVars prints undefined compose variables with a hint to set them
+func reportUndefinedVars(missing []string) {
+	fmt.Println()
+	ui.Warning("Compose references undefined variables:")
+	for _, name := range missing {
+		fmt.Printf("  • %s\n", name)
+	}
+	ui.Info("Set them with 'musing secrets set <name>'")
+}
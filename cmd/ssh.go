@@ -15,8 +15,7 @@ var sshCmd = &cobra.Command{
 	Short: "Open interactive SSH session to production server",
 	Long:  `Open an interactive SSH session to the production server configured in .musing.yaml.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		config.MustFindProjectRoot()
-		cfg := config.GetConfig()
+		cfg := projectFrom(cmd).Config
 
 		if cfg.Production == nil {
 			return fmt.Errorf("production configuration not found in .musing.yaml")
@@ -59,18 +58,22 @@ func buildSSHArgs(cfg *config.ProjectConfig, withTunnel bool) []string {
 			prodPort = 27019
 		}
 
-		remotePort := cfg.Production.RemoteDBPort
-		if remotePort == 0 {
-			remotePort = 27017
-		}
-
-		args = append(args, "-L", fmt.Sprintf("%d:localhost:%d", prodPort, remotePort))
+		args = append(args, "-L", fmt.Sprintf("%d:localhost:%d", prodPort, remoteDBPort(cfg)))
 	}
 
 	args = append(args, cfg.Production.Server)
 	return args
 }
 
+// remoteDBPort returns the database port on the production server: production.remoteDBPort,
+// or the database type's standard port
+func remoteDBPort(cfg *config.ProjectConfig) int {
+	if cfg.Production.RemoteDBPort == 0 {
+		return cfg.Database.DefaultPort()
+	}
+	return cfg.Production.RemoteDBPort
+}
+
 // expandHomeDir expands ~ to the user's home directory
 func expandHomeDir(path string) string {
 	if strings.HasPrefix(path, "~/") {
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stevengregory/musing-cli/internal/config"
+)
+
+// TestBuildSSHArgs tests SSH argument construction with and without a tunnel
+func TestBuildSSHArgs(t *testing.T) {
+	cfg := &config.ProjectConfig{
+		Database: config.DatabaseConfig{DevPort: 27018, ProdPort: 27020},
+		Production: &config.ProductionConfig{
+			Server:       "root@example.com",
+			RemoteDBPort: 27017,
+			SSHKeyPath:   "/keys/id_ed25519",
+		},
+	}
+
+	tests := []struct {
+		name       string
+		withTunnel bool
+		expected   []string
+	}{
+		{
+			name:     "interactive session",
+			expected: []string{"-i", "/keys/id_ed25519", "root@example.com"},
+		},
+		{
+			name:       "tunnel",
+			withTunnel: true,
+			expected:   []string{"-i", "/keys/id_ed25519", "-f", "-N", "-L", "27020:localhost:27017", "root@example.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := buildSSHArgs(cfg, tt.withTunnel)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("buildSSHArgs() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestGenerateTunnelCommand tests the suggested tunnel command with and without production config
+func TestGenerateTunnelCommand(t *testing.T) {
+	cfg := &config.ProjectConfig{
+		Database: config.DatabaseConfig{DevPort: 27018, ProdPort: 27019},
+	}
+	if got, want := generateTunnelCommand(cfg), "ssh -f -N -L 27019:localhost:27018 <your-server>"; got != want {
+		t.Errorf("generateTunnelCommand() = %q, want %q", got, want)
+	}
+
+	cfg.Production = &config.ProductionConfig{Server: "root@example.com", RemoteDBPort: 27017}
+	if got, want := generateTunnelCommand(cfg), "ssh -f -N -L 27019:localhost:27017 root@example.com"; got != want {
+		t.Errorf("generateTunnelCommand() = %q, want %q", got, want)
+	}
+}
+
+// TestRemoteDBPort tests the production database port defaults to its type's standard port
+func TestRemoteDBPort(t *testing.T) {
+	tests := []struct {
+		database config.DatabaseConfig
+		remote   int
+		expected int
+	}{
+		{database: config.DatabaseConfig{Type: "MongoDB", DevPort: 27018}, expected: 27017},
+		{database: config.DatabaseConfig{Type: "postgres", DevPort: 5433}, expected: 5432},
+		{database: config.DatabaseConfig{Type: "redis"}, expected: 6379},
+		{database: config.DatabaseConfig{Type: "cassandra", DevPort: 9042}, expected: 9042},
+		{database: config.DatabaseConfig{Type: "postgres"}, remote: 6543, expected: 6543},
+	}
+
+	for _, tt := range tests {
+		cfg := &config.ProjectConfig{Database: tt.database, Production: &config.ProductionConfig{RemoteDBPort: tt.remote}}
+		if got := remoteDBPort(cfg); got != tt.expected {
+			t.Errorf("remoteDBPort(%s, remote %d) = %d, expected %d", tt.database.Type, tt.remote, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/stevengregory/musing-cli/internal/config"
+	"github.com/stevengregory/musing-cli/internal/health"
+	"github.com/stevengregory/musing-cli/internal/ui"
+	"gopkg.in/yaml.v3"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Check stack health once",
+	Long: `Run the same health checks as 'musing monitor' once and print the result.
+
+Exits with status 1 if any required service is down, so scripts, git hooks and
+CI jobs can gate on stack health. Services marked 'optional: true' and the
+production SSH tunnel are reported but never fail the check.
+
+With --history, summarise the health history recorded by 'musing monitor --record'
+instead: uptime, p50/p95 latency, status changes and flapping per service.`,
+	Example: `  musing status
+  musing status --json | jq '.services[] | select(.status != "running")'
+  musing status --yaml
+  musing status --history`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		asJSON, _ := cmd.Flags().GetBool("json")
+		asYAML, _ := cmd.Flags().GetBool("yaml")
+		history, _ := cmd.Flags().GetBool("history")
+
+		var format string
+		switch {
+		case asJSON && asYAML:
+			ui.Error("--json and --yaml can't be used together")
+			return fmt.Errorf("--json and --yaml are mutually exclusive")
+		case asJSON:
+			format = "json"
+		case asYAML:
+			format = "yaml"
+		}
+
+		if history {
+			return runStatusHistory(projectFrom(cmd), format)
+		}
+		return runStatus(projectFrom(cmd), format)
+	},
+}
+
+func init() {
+	statusCmd.Flags().Bool("json", false, "Print the result as JSON")
+	statusCmd.Flags().Bool("yaml", false, "Print the result as YAML")
+	statusCmd.Flags().Bool("history", false, "Summarise recorded health history instead of checking now")
+}
+
+// statusReport is the machine-readable result of 'musing status'
+type statusReport struct {
+	Project   string          `json:"project" yaml:"project"`
+	Healthy   bool            `json:"healthy" yaml:"healthy"` // No required service is down
+	CheckedAt time.Time       `json:"checkedAt" yaml:"checkedAt"`
+	Services  []serviceReport `json:"services" yaml:"services"`
+}
+
+// serviceReport is one service's entry in a statusReport
+type serviceReport struct {
+	Name      string  `json:"name" yaml:"name"`
+	Section   string  `json:"section" yaml:"section"`
+	Port      int     `json:"port,omitempty" yaml:"port,omitempty"`
+	Status    string  `json:"status" yaml:"status"`
+	Required  bool    `json:"required" yaml:"required"`
+	LatencyMs float64 `json:"latencyMs,omitempty" yaml:"latencyMs,omitempty"`
+	Detail    string  `json:"detail,omitempty" yaml:"detail,omitempty"`
+	Error     string  `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// historyReport is one service's entry in 'musing status --history'
+type historyReport struct {
+	Name        string    `json:"name" yaml:"name"`
+	Samples     int       `json:"samples" yaml:"samples"`
+	Status      string    `json:"status" yaml:"status"`
+	Since       time.Time `json:"since" yaml:"since"`
+	Uptime      float64   `json:"uptimePercent" yaml:"uptimePercent"`
+	P50Ms       float64   `json:"p50Ms" yaml:"p50Ms"`
+	P95Ms       float64   `json:"p95Ms" yaml:"p95Ms"`
+	Transitions int       `json:"transitions" yaml:"transitions"`
+	Flapping    bool      `json:"flapping" yaml:"flapping"`
+}
+
+// runStatus checks the stack once, printing the result as text or in format ("json" or "yaml")
+func runStatus(project *config.Project, format string) error {
+	services, checks := stackChecks(project.Config)
+	results := health.Checker{}.Run(context.Background(), checks)
+
+	report := buildStatusReport(filepath.Base(project.Root), services, results)
+
+	printed, err := printStructured(report, format)
+	if err != nil {
+		return err
+	}
+	if !printed {
+		printStatusReport(report, stackSections(project.Config), thresholdsFor(project.Config))
+	}
+
+	if !report.Healthy {
+		return fmt.Errorf("%d required service(s) down", len(downServices(report)))
+	}
+	return nil
+}
+
+// runStatusHistory summarises the recorded health history, as text or in format
+func runStatusHistory(project *config.Project, format string) error {
+	history, err := health.LoadHistory(project.HealthHistoryPath(), health.DefaultHistorySize)
+	if err != nil {
+		return fmt.Errorf("failed to load health history: %w", err)
+	}
+
+	var reports []historyReport
+	for _, service := range history.Services() {
+		stats := history.Stats(service)
+		reports = append(reports, historyReport{
+			Name:        service,
+			Samples:     stats.Samples,
+			Status:      stats.Status,
+			Since:       stats.Since,
+			Uptime:      stats.Uptime,
+			P50Ms:       float64(stats.P50.Microseconds()) / 1000,
+			P95Ms:       float64(stats.P95.Microseconds()) / 1000,
+			Transitions: stats.Transitions,
+			Flapping:    stats.Flapping,
+		})
+	}
+
+	if printed, err := printStructured(reports, format); printed || err != nil {
+		return err
+	}
+
+	if len(reports) == 0 {
+		fmt.Println("No health history recorded yet. Run 'musing monitor --record' to start recording.")
+		return nil
+	}
+
+	fmt.Println(sectionHeaderStyle.Render("━━━ Health History ━━━"))
+	for _, r := range reports {
+		line := fmt.Sprintf("  %-25s %6.1f%% up  p50 %-9s p95 %-9s %s for %s, %d change(s)",
+			r.Name,
+			r.Uptime,
+			formatMs(r.P50Ms),
+			formatMs(r.P95Ms),
+			r.Status,
+			time.Since(r.Since).Round(time.Second),
+			r.Transitions,
+		)
+		if r.Flapping {
+			line += " " + statusDegradedStyle.Render("flapping")
+		}
+		fmt.Println(line)
+	}
+	return nil
+}
+
+// formatMs formats a millisecond latency, or "-" when none was recorded
+func formatMs(ms float64) string {
+	if ms == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1fms", ms)
+}
+
+// printStructured prints v in format ("json" or "yaml"), reporting whether it did
+func printStructured(v any, format string) (bool, error) {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return true, encoder.Encode(v)
+	case "yaml":
+		return true, yaml.NewEncoder(os.Stdout).Encode(v)
+	}
+	return false, nil
+}
+
+// buildStatusReport combines the stack's services with their check results
+func buildStatusReport(project string, services []ServiceHealth, results []health.Result) statusReport {
+	report := statusReport{
+		Project:   project,
+		Healthy:   true,
+		CheckedAt: time.Now(),
+	}
+
+	for i, svc := range services {
+		result := results[i]
+		entry := serviceReport{
+			Name:     svc.Name,
+			Section:  svc.Section,
+			Port:     svc.Port,
+			Status:   result.Status,
+			Required: !svc.Optional,
+			Detail:   result.Detail,
+		}
+		if result.Status != health.StatusDown {
+			// A down check's latency is only how long it took to give up
+			entry.LatencyMs = float64(result.Latency.Microseconds()) / 1000
+		}
+		if result.Error != nil && result.Status != health.StatusRunning {
+			entry.Error = result.Error.Error()
+		}
+		if entry.Required && entry.Status == health.StatusDown {
+			report.Healthy = false
+		}
+		report.Services = append(report.Services, entry)
+	}
+
+	return report
+}
+
+// downServices returns the names of required services that are down
+func downServices(report statusReport) []string {
+	var names []string
+	for _, svc := range report.Services {
+		if svc.Required && svc.Status == health.StatusDown {
+			names = append(names, svc.Name)
+		}
+	}
+	return names
+}
+
+// printStatusReport prints the sectioned text view used by 'musing monitor'
+func printStatusReport(report statusReport, sections []config.MonitorSectionConfig, thresholds latencyThresholds) {
+	var services []ServiceHealth
+	for _, svc := range report.Services {
+		services = append(services, ServiceHealth{
+			Name:    svc.Name,
+			Port:    svc.Port,
+			Status:  svc.Status,
+			Detail:  svc.Detail,
+			Section: svc.Section,
+			Latency: time.Duration(svc.LatencyMs * float64(time.Millisecond)),
+			Error:   svc.Error,
+		})
+	}
+
+	for _, group := range groupSections(sections, services) {
+		fmt.Println(sectionHeaderStyle.Render(group.title()))
+		fmt.Print(renderServiceList(group.Services, -1, thresholds))
+	}
+
+	fmt.Println()
+	if report.Healthy {
+		fmt.Println(statusRunningStyle.Render("✓ All required services are healthy"))
+	} else {
+		down := downServices(report)
+		fmt.Println(statusDownStyle.Render(fmt.Sprintf("✗ %d required service(s) down: %s", len(down), strings.Join(down, ", "))))
+	}
+}
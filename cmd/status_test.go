@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stevengregory/musing-cli/internal/health"
+)
+
+// TestBuildStatusReport tests that only required services that are down make the stack unhealthy
+func TestBuildStatusReport(t *testing.T) {
+	tests := []struct {
+		name     string
+		optional bool
+		status   string
+		healthy  bool
+	}{
+		{"required running", false, health.StatusRunning, true},
+		{"required degraded", false, health.StatusDegraded, true},
+		{"required down", false, health.StatusDown, false},
+		{"optional down", true, health.StatusDown, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services := []ServiceHealth{{Name: "api", Port: 8080, Section: SectionAPI, Optional: tt.optional}}
+			results := []health.Result{{Status: tt.status}}
+
+			report := buildStatusReport("proj", services, results)
+			if report.Healthy != tt.healthy {
+				t.Errorf("Healthy = %v, expected %v", report.Healthy, tt.healthy)
+			}
+			if report.Services[0].Required == tt.optional {
+				t.Errorf("Required = %v for optional = %v", report.Services[0].Required, tt.optional)
+			}
+		})
+	}
+}
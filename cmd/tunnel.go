@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os/exec"
 	"strconv"
@@ -18,7 +19,7 @@ var tunnelCmd = &cobra.Command{
 	Long:  `Start, stop, or check status of SSH tunnel for production database access.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		// Default to start
-		return tunnelStart()
+		return tunnelStart(projectFrom(cmd).Config)
 	},
 }
 
@@ -27,7 +28,7 @@ var tunnelStartCmd = &cobra.Command{
 	Short: "Start SSH tunnel",
 	Long:  `Start SSH tunnel for production database access.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return tunnelStart()
+		return tunnelStart(projectFrom(cmd).Config)
 	},
 }
 
@@ -36,7 +37,7 @@ var tunnelStopCmd = &cobra.Command{
 	Short: "Stop SSH tunnel",
 	Long:  `Stop the SSH tunnel to production database.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return tunnelStop()
+		return tunnelStop(projectFrom(cmd).Config)
 	},
 }
 
@@ -45,7 +46,7 @@ var tunnelStatusCmd = &cobra.Command{
 	Short: "Check tunnel status",
 	Long:  `Check if the SSH tunnel is currently running.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return tunnelStatus()
+		return tunnelStatus(projectFrom(cmd).Config)
 	},
 }
 
@@ -55,10 +56,7 @@ func init() {
 	tunnelCmd.AddCommand(tunnelStatusCmd)
 }
 
-func tunnelStart() error {
-	config.MustFindProjectRoot()
-	cfg := config.GetConfig()
-
+func tunnelStart(cfg *config.ProjectConfig) error {
 	if cfg.Production == nil {
 		return fmt.Errorf("production configuration not found in .musing.yaml")
 	}
@@ -69,8 +67,8 @@ func tunnelStart() error {
 	}
 
 	// Check if tunnel is already running
-	if health.CheckPort(prodPort).Open {
-		return tunnelStatus()
+	if health.CheckPort(context.Background(), prodPort).Open {
+		return tunnelStatus(cfg)
 	}
 
 	// Build SSH command with tunnel using shared helper
@@ -105,10 +103,7 @@ func tunnelStart() error {
 	successStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("10"))
 	infoStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("99"))
 
-	remotePort := cfg.Production.RemoteDBPort
-	if remotePort == 0 {
-		remotePort = 27017
-	}
+	remotePort := remoteDBPort(cfg)
 
 	fmt.Println()
 	fmt.Println(successStyle.Render("✓") + " SSH tunnel started")
@@ -121,17 +116,14 @@ func tunnelStart() error {
 	return nil
 }
 
-func tunnelStop() error {
-	config.MustFindProjectRoot()
-	cfg := config.GetConfig()
-
+func tunnelStop(cfg *config.ProjectConfig) error {
 	prodPort := cfg.Database.ProdPort
 	if prodPort == 0 {
 		prodPort = 27019
 	}
 
 	// Check if tunnel is running
-	if !health.CheckPort(prodPort).Open {
+	if !health.CheckPort(context.Background(), prodPort).Open {
 		warningStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("11"))
 		fmt.Println()
 		fmt.Println(warningStyle.Render("✓") + " SSH tunnel is not running")
@@ -161,10 +153,7 @@ func tunnelStop() error {
 	return nil
 }
 
-func tunnelStatus() error {
-	config.MustFindProjectRoot()
-	cfg := config.GetConfig()
-
+func tunnelStatus(cfg *config.ProjectConfig) error {
 	if cfg.Production == nil {
 		warningStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("11"))
 		fmt.Println(warningStyle.Render("✗") + " Production configuration not found in .musing.yaml")
@@ -186,7 +175,7 @@ func tunnelStatus() error {
 	fmt.Println(headerStyle.Render("SSH Tunnel Status"))
 	fmt.Println()
 
-	portStatus := health.CheckPort(prodPort)
+	portStatus := health.CheckPort(context.Background(), prodPort)
 	var statusIcon string
 	var statusText string
 
@@ -206,10 +195,7 @@ func tunnelStatus() error {
 	fmt.Println(infoStyle.Render("  Local port:  ") + strconv.Itoa(prodPort))
 	fmt.Println(infoStyle.Render("  Remote:      ") + cfg.Production.Server)
 
-	remotePort := cfg.Production.RemoteDBPort
-	if remotePort == 0 {
-		remotePort = 27017
-	}
+	remotePort := remoteDBPort(cfg)
 	fmt.Println(infoStyle.Render("  Remote port: ") + strconv.Itoa(remotePort))
 
 	if !portStatus.Open {
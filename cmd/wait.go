@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/stevengregory/musing-cli/internal/config"
+	"github.com/stevengregory/musing-cli/internal/health"
+	"github.com/stevengregory/musing-cli/internal/ui"
+)
+
+// defaultStartTimeout is how long to wait for a service that doesn't set startTimeout
+const defaultStartTimeout = 60 * time.Second
+
+// readinessPollInterval is the pause between rounds of checks while waiting
+const readinessPollInterval = time.Second
+
+// readinessItem tracks one service while waiting for the stack to become healthy
+type readinessItem struct {
+	section string
+	service ServiceHealth
+	check   health.Check
+	timeout time.Duration
+	done    bool          // Healthy, or gave up after timeout
+	elapsed time.Duration // Time until healthy, or waited so far
+	err     error         // Last check error
+}
+
+// readinessItems returns the database and every configured service, grouped by section
+func readinessItems(cfg *config.ProjectConfig) []*readinessItem {
+	sections := stackSections(cfg)
+	items := []*readinessItem{{
+		section: sectionFor(sections, cfg.Database.Type, KindDatabase),
+		service: ServiceHealth{Name: cfg.Database.Type, Port: cfg.Database.DevPort},
+		check:   health.DatabaseCheck(cfg.Database.Type, cfg.Database.Type, cfg.Database.DevPort),
+		timeout: startTimeout(cfg.Database.StartTimeout),
+	}}
+
+	for _, svc := range cfg.Services {
+		items = append(items, &readinessItem{
+			section: sectionFor(sections, svc.Name, svc.Type),
+			service: ServiceHealth{Name: svc.Name, Port: svc.Port},
+			check:   serviceCheck(svc),
+			timeout: startTimeout(svc.StartTimeout),
+		})
+	}
+
+	// Group items by section so each header is printed once
+	sort.SliceStable(items, func(i, j int) bool {
+		return sectionIndex(sections, items[i].section) < sectionIndex(sections, items[j].section)
+	})
+
+	return items
+}
+
+// startTimeout returns the configured start timeout, or the default
+func startTimeout(configured time.Duration) time.Duration {
+	if configured > 0 {
+		return configured
+	}
+	return defaultStartTimeout
+}
+
+// pendingChecks returns the checks, and item indexes, of services still being waited on
+func pendingChecks(items []*readinessItem) ([]int, []health.Check) {
+	var indexes []int
+	var checks []health.Check
+	for i, item := range items {
+		if !item.done {
+			indexes = append(indexes, i)
+			checks = append(checks, item.check)
+		}
+	}
+	return indexes, checks
+}
+
+// runReadinessChecks runs one round of checks
+func runReadinessChecks(checks []health.Check) []health.Result {
+	checker := health.Checker{Deadline: 5 * time.Second}
+	return checker.Run(context.Background(), checks)
+}
+
+// applyReadiness records a round of results, marking services healthy or timed out
+func applyReadiness(items []*readinessItem, indexes []int, results []health.Result, started time.Time) {
+	for _, result := range results {
+		item := items[indexes[result.Index]]
+		item.service.Status = result.Status
+		item.service.Detail = result.Detail
+		item.err = result.Error
+		item.elapsed = time.Since(started)
+
+		if result.Status == health.StatusRunning || item.elapsed >= item.timeout {
+			item.done = true
+		}
+	}
+}
+
+// readinessDone reports whether every service is healthy or has timed out
+func readinessDone(items []*readinessItem) bool {
+	for _, item := range items {
+		if !item.done {
+			return false
+		}
+	}
+	return true
+}
+
+// unhealthyItems returns the services that timed out without becoming healthy
+func unhealthyItems(items []*readinessItem) []*readinessItem {
+	var failed []*readinessItem
+	for _, item := range items {
+		if item.done && item.service.Status != health.StatusRunning {
+			failed = append(failed, item)
+		}
+	}
+	return failed
+}
+
+// waitForHealthy polls each service's health check until all are healthy or have
+// passed their start timeout, rendering a live checklist. It returns an error naming
+// the services that never came up
+func waitForHealthy(cfg *config.ProjectConfig) error {
+	items := readinessItems(cfg)
+
+	if ui.IsTTY() {
+		p := tea.NewProgram(readinessModel{items: items, spinner: newReadinessSpinner(), started: time.Now()})
+		model, err := p.Run()
+		if err != nil {
+			return err
+		}
+		if model.(readinessModel).cancelled {
+			return fmt.Errorf("cancelled by user")
+		}
+	} else {
+		started := time.Now()
+		for !readinessDone(items) {
+			indexes, checks := pendingChecks(items)
+			applyReadiness(items, indexes, runReadinessChecks(checks), started)
+			if !readinessDone(items) {
+				time.Sleep(readinessPollInterval)
+			}
+		}
+		fmt.Print(renderReadiness(items, ""))
+	}
+
+	failed := unhealthyItems(items)
+	if len(failed) == 0 {
+		return nil
+	}
+
+	fmt.Println()
+	for _, item := range failed {
+		ui.Error(fmt.Sprintf("%s did not become healthy: %v", item.service.Name, item.err))
+	}
+	return fmt.Errorf("%d service(s) did not become healthy", len(failed))
+}
+
+// renderReadiness renders the checklist grouped by section. spin is shown for services still waiting
+func renderReadiness(items []*readinessItem, spin string) string {
+	sectionStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FF00FF"))
+
+	var s, section string
+	for _, item := range items {
+		if item.section != section {
+			section = item.section
+			s += "\n" + sectionStyle.Render(fmt.Sprintf("━━━ %s ━━━", section)) + "\n\n"
+		}
+
+		var icon, note string
+		switch {
+		case !item.done:
+			icon = spin
+			note = fmt.Sprintf("waiting %s", item.elapsed.Round(time.Second))
+			if item.service.Status == health.StatusDegraded {
+				note += " (degraded)"
+			}
+		case item.service.Status == health.StatusRunning:
+			icon = statusRunningStyle.Render("✓")
+			note = fmt.Sprintf("ready in %s", item.elapsed.Round(100*time.Millisecond))
+			if item.service.Detail != "" {
+				note += " • " + item.service.Detail
+			}
+		default:
+			icon = statusDownStyle.Render("✗")
+			note = fmt.Sprintf("timed out after %s", item.timeout)
+			if item.err != nil {
+				note += ": " + item.err.Error()
+			}
+		}
+
+		s += fmt.Sprintf("  %s %-25s :%-6d %s\n", icon, item.service.Name, item.service.Port, detailStyle.Render(note))
+	}
+	return s
+}
+
+// readinessModel renders the live checklist while polling
+type readinessModel struct {
+	items     []*readinessItem
+	spinner   spinner.Model
+	started   time.Time
+	cancelled bool
+}
+
+// readinessPolledMsg carries the results of a round of checks
+type readinessPolledMsg struct {
+	indexes []int
+	results []health.Result
+}
+
+// readinessPollMsg asks for the next round of checks
+type readinessPollMsg struct{}
+
+func newReadinessSpinner() spinner.Model {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF00FF"))
+	return s
+}
+
+func (m readinessModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, m.poll())
+}
+
+// poll runs a round of checks for the services still pending
+func (m readinessModel) poll() tea.Cmd {
+	indexes, checks := pendingChecks(m.items)
+	return func() tea.Msg {
+		return readinessPolledMsg{indexes: indexes, results: runReadinessChecks(checks)}
+	}
+}
+
+func (m readinessModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			m.cancelled = true
+			return m, tea.Quit
+		}
+
+	case readinessPolledMsg:
+		applyReadiness(m.items, msg.indexes, msg.results, m.started)
+		if readinessDone(m.items) {
+			return m, tea.Quit
+		}
+		return m, tea.Tick(readinessPollInterval, func(time.Time) tea.Msg {
+			return readinessPollMsg{}
+		})
+
+	case readinessPollMsg:
+		return m, m.poll()
+
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+
+	return m, nil
+}
+
+func (m readinessModel) View() string {
+	return renderReadiness(m.items, m.spinner.View())
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stevengregory/musing-cli/internal/health"
+)
+
+// TestApplyReadiness tests that services finish when healthy or past their start timeout
+func TestApplyReadiness(t *testing.T) {
+	check := func(status string) health.Check {
+		return health.Check{Run: func(ctx context.Context) health.Result {
+			return health.Result{Status: status, Error: errors.New(status)}
+		}}
+	}
+
+	items := []*readinessItem{
+		{service: ServiceHealth{Name: "healthy"}, check: check(health.StatusRunning), timeout: time.Minute},
+		{service: ServiceHealth{Name: "starting"}, check: check(health.StatusDown), timeout: time.Minute},
+		{service: ServiceHealth{Name: "broken"}, check: check(health.StatusDegraded), timeout: time.Millisecond},
+	}
+
+	started := time.Now().Add(-time.Second)
+	indexes, checks := pendingChecks(items)
+	applyReadiness(items, indexes, runReadinessChecks(checks), started)
+
+	expectedDone := []bool{true, false, true}
+	for i, item := range items {
+		if item.done != expectedDone[i] {
+			t.Errorf("%s: done = %v, expected %v", item.service.Name, item.done, expectedDone[i])
+		}
+	}
+
+	if readinessDone(items) {
+		t.Error("readinessDone() = true with a service still starting")
+	}
+
+	failed := unhealthyItems(items)
+	if len(failed) != 1 || failed[0].service.Name != "broken" || failed[0].err == nil {
+		t.Errorf("unhealthyItems() = %v, expected only broken with its last error", failed)
+	}
+
+	// Only the service still starting is checked next round
+	if indexes, _ := pendingChecks(items); len(indexes) != 1 || indexes[0] != 1 {
+		t.Errorf("pendingChecks() indexes = %v, expected [1]", indexes)
+	}
+}
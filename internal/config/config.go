@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -12,14 +14,75 @@ import (
 type ProjectConfig struct {
 	Services   []ServiceConfig   `yaml:"services"`
 	Database   DatabaseConfig    `yaml:"database"`
+	Compose    ComposeConfig     `yaml:"compose"`
+	Secrets    SecretsConfig     `yaml:"secrets"`
 	Production *ProductionConfig `yaml:"production,omitempty"` // Optional production config
+	Notify     *NotifyConfig     `yaml:"notify,omitempty"`     // Optional notifications on health changes
+	Monitor    MonitorConfig     `yaml:"monitor"`
 }
 
 // ServiceConfig represents a service in the stack
 type ServiceConfig struct {
-	Name string `yaml:"name"`
-	Port int    `yaml:"port"`
-	Type string `yaml:"type"` // frontend, api, database
+	Name         string        `yaml:"name"`
+	Port         int           `yaml:"port"`
+	Type         string        `yaml:"type"`           // frontend, api, database
+	Repo         *RepoConfig   `yaml:"repo,omitempty"` // Optional source repository location
+	StartTimeout time.Duration `yaml:"startTimeout"`   // How long 'musing dev' waits for it to become healthy (default: 60s)
+	Optional     bool          `yaml:"optional"`       // Don't fail 'musing status' when this service is down
+	Interval     time.Duration `yaml:"interval"`       // How often 'musing monitor' checks it (default: the monitor interval)
+	Compose      string        `yaml:"compose"`        // Compose service name, when it differs from name
+	ProdPort     int           `yaml:"prodPort"`       // Port on the production server, for 'monitor --prod' (default: port)
+
+	Healthcheck *HealthcheckConfig `yaml:"healthcheck,omitempty"` // Optional HTTP or gRPC health check (default: TCP port check)
+}
+
+// ProductionPort returns the port the service listens on in production
+func (s ServiceConfig) ProductionPort() int {
+	if s.ProdPort != 0 {
+		return s.ProdPort
+	}
+	return s.Port
+}
+
+// ComposeService returns the docker compose service that runs this service
+func (s ServiceConfig) ComposeService() string {
+	if s.Compose != "" {
+		return s.Compose
+	}
+	return s.Name
+}
+
+// HealthcheckConfig represents an HTTP or gRPC health check for a service
+type HealthcheckConfig struct {
+	Type      string            `yaml:"type"`      // http (default) or grpc
+	Path      string            `yaml:"path"`      // Request path on localhost:<port> (e.g. /health)
+	URL       string            `yaml:"url"`       // Full URL, overrides path (e.g. https://localhost:8443/health)
+	Status    []int             `yaml:"status"`    // Accepted status codes (default: any 2xx)
+	Body      string            `yaml:"body"`      // Substring the response body must contain
+	JSONField string            `yaml:"jsonField"` // Dot-separated JSON field to check (e.g. status)
+	JSONValue string            `yaml:"jsonValue"` // Expected value of jsonField (default: field must exist)
+	Timeout   time.Duration     `yaml:"timeout"`   // Request timeout (e.g. 2s, default: 3s)
+	Headers   map[string]string `yaml:"headers"`   // Extra request headers
+
+	// gRPC checks call grpc.health.v1.Health/Check on localhost:<port>
+	Service       string `yaml:"service"`       // Service name to check (default: the whole server)
+	TLS           bool   `yaml:"tls"`           // Connect with TLS instead of plaintext
+	TLSSkipVerify bool   `yaml:"tlsSkipVerify"` // Accept self-signed certificates
+}
+
+// RepoConfig represents the git repository backing a service
+type RepoConfig struct {
+	Path   string `yaml:"path"`   // Repo directory, relative to project root (default: ../<service name>)
+	URL    string `yaml:"url"`    // Git remote URL or local bare repo path used by 'musing repos clone'
+	Branch string `yaml:"branch"` // Optional branch to check out when cloning
+}
+
+// Repo is a resolved service repository
+type Repo struct {
+	Name   string // Service name
+	Path   string // Absolute path to the repo
+	URL    string // Clone URL (may be empty)
+	Branch string // Branch to clone (may be empty)
 }
 
 // DatabaseConfig represents database configuration
@@ -29,117 +92,195 @@ type DatabaseConfig struct {
 	DevPort  int    `yaml:"devPort"`
 	ProdPort int    `yaml:"prodPort"`
 	DataDir  string `yaml:"dataDir"` // Relative path to data directory
+	Compose  string `yaml:"compose"` // Optional compose service running it, for monitor actions and stats
+
+	StartTimeout time.Duration `yaml:"startTimeout"` // How long 'musing dev' waits for it to become healthy (default: 60s)
 }
 
-// ProductionConfig represents optional production deployment settings
-type ProductionConfig struct {
-	Server       string `yaml:"server"`       // SSH server (e.g., "root@your-server.com")
-	RemoteDBPort int    `yaml:"remoteDBPort"` // Remote database port (typically same as devPort)
-	SSHKeyPath   string `yaml:"sshKeyPath"`   // Optional SSH key path (e.g., "~/.ssh/digital-ocean/id_ed25519")
+// DefaultPort returns the standard port for the database type, or devPort for other types
+func (d DatabaseConfig) DefaultPort() int {
+	switch strings.ToLower(d.Type) {
+	case "mongodb", "mongo":
+		return 27017
+	case "postgres", "postgresql":
+		return 5432
+	case "redis":
+		return 6379
+	case "mysql", "mariadb":
+		return 3306
+	}
+	return d.DevPort
 }
 
-var currentConfig *ProjectConfig
+// ComposeConfig represents Docker Compose settings
+type ComposeConfig struct {
+	Files   []string `yaml:"files"`   // Compose files relative to project root (default: compose.yaml and friends)
+	Project string   `yaml:"project"` // Optional compose project name (passed as -p)
+}
 
-// FindProjectRoot searches upward from CWD for a directory containing .musing.yaml
-// and loads the project configuration
-func FindProjectRoot() (string, error) {
-	currentDir, err := os.Getwd()
-	if err != nil {
-		return "", fmt.Errorf("failed to get current directory: %w", err)
-	}
+// SecretsConfig represents local secrets settings
+type SecretsConfig struct {
+	KeyFile string `yaml:"keyFile"` // Optional key file (default: passphrase via MUSING_SECRETS_PASSPHRASE or prompt)
+	EnvFile string `yaml:"envFile"` // File secrets are written to on 'musing dev start' and compose reads (default: .env)
+}
 
-	// Search upward from current directory
-	dir := currentDir
-	for {
-		// Check if this directory contains .musing.yaml file
-		musingPath := filepath.Join(dir, ".musing.yaml")
-		if _, err := os.Stat(musingPath); err == nil {
-			// Found .musing.yaml file, load config
-			if err := loadConfig(musingPath); err != nil {
-				return "", fmt.Errorf("failed to load config from %s: %w", musingPath, err)
-			}
+// MonitorConfig represents 'musing monitor' display settings
+type MonitorConfig struct {
+	Interval        time.Duration `yaml:"interval"`        // How often checks run (default: 3s, 10s with --serve)
+	LatencyWarn     time.Duration `yaml:"latencyWarn"`     // Show check latency in amber above this (default: 250ms)
+	LatencyCritical time.Duration `yaml:"latencyCritical"` // Show check latency in red above this (default: 1s)
 
-			// Verify compose.yaml exists
-			if !hasComposeFile(dir) {
-				return "", fmt.Errorf("found .musing.yaml at %s but no compose.yaml", dir)
-			}
+	Sections []MonitorSectionConfig `yaml:"sections"` // Optional groups of services, in display order
+}
 
-			return dir, nil
-		}
+// MonitorSectionConfig represents a group of services in 'musing monitor' and 'musing status'
+type MonitorSectionConfig struct {
+	Name      string   `yaml:"name"`
+	Icon      string   `yaml:"icon"`      // Optional: shown before the name
+	Types     []string `yaml:"types"`     // Service types it holds; docker, database and tunnel are built in
+	Services  []string `yaml:"services"`  // Services it holds by name, whatever their type
+	Collapsed bool     `yaml:"collapsed"` // Start collapsed in the monitor
+}
 
-		// Move to parent directory
-		parent := filepath.Dir(dir)
-		if parent == dir {
-			// Reached filesystem root
-			break
-		}
-		dir = parent
-	}
+// NotifyConfig represents notifications sent when a service's health changes
+type NotifyConfig struct {
+	Debounce  time.Duration    `yaml:"debounce"`  // How long a new status must hold before notifying (e.g. 30s)
+	Notifiers []NotifierConfig `yaml:"notifiers"` // Where to send notifications
+}
 
-	return "", fmt.Errorf("no .musing.yaml file found (searched upward from %s)", currentDir)
+// NotifierConfig represents one notification target
+type NotifierConfig struct {
+	Type     string            `yaml:"type"`     // command, webhook or slack
+	Command  string            `yaml:"command"`  // command: shell command, with MUSING_SERVICE, MUSING_STATUS, etc. set
+	URL      string            `yaml:"url"`      // webhook/slack: URL to POST to
+	Body     string            `yaml:"body"`     // webhook: Go template for the JSON body (default: the event as JSON)
+	Headers  map[string]string `yaml:"headers"`  // webhook: extra request headers
+	Services []string          `yaml:"services"` // Optional: only notify about these services
 }
 
-// loadConfig reads and parses the .musing.yaml configuration file
-func loadConfig(configPath string) error {
+// ProductionConfig represents optional production deployment settings
+type ProductionConfig struct {
+	Server       string `yaml:"server"`       // SSH server (e.g., "root@your-server.com")
+	RemoteDBPort int    `yaml:"remoteDBPort"` // Remote database port (default: the database type's standard port)
+	SSHKeyPath   string `yaml:"sshKeyPath"`   // Optional SSH key path (e.g., "~/.ssh/digital-ocean/id_ed25519")
+
+	Checks []RemoteCheckConfig `yaml:"checks"` // Optional remote endpoint checks shown alongside the tunnel
+
+	// 'musing monitor --prod' checks the server over SSH
+	ComposeDir     string        `yaml:"composeDir"`     // Server directory docker compose runs in (default: the login directory)
+	ComposeProject string        `yaml:"composeProject"` // Optional compose project name on the server (passed as -p)
+	Interval       time.Duration `yaml:"interval"`       // How often production is checked (default: 15s)
+}
+
+// RemoteCheckConfig represents a check of a remote production endpoint
+type RemoteCheckConfig struct {
+	Name     string        `yaml:"name"`
+	URL      string        `yaml:"url"`      // HTTP(S) URL; https URLs also get certificate checks
+	Host     string        `yaml:"host"`     // host:port, when checking a TCP or TLS endpoint instead of a URL
+	TLS      bool          `yaml:"tls"`      // Perform a TLS handshake on host
+	Status   []int         `yaml:"status"`   // Accepted HTTP status codes (default: any 2xx)
+	WarnDays int           `yaml:"warnDays"` // Degrade when the certificate expires within this many days (default: 14)
+	Timeout  time.Duration `yaml:"timeout"`  // Default: 5s
+}
+
+// defaultComposeFiles lists the file names docker compose looks for, in order of preference
+var defaultComposeFiles = []string{
+	"compose.yaml",
+	"compose.yml",
+	"docker-compose.yaml",
+	"docker-compose.yml",
+}
+
+// ConfigFileName is the project configuration file musing looks for
+const ConfigFileName = ".musing.yaml"
+
+// LoadConfig reads and parses a .musing.yaml configuration file
+func LoadConfig(configPath string) (*ProjectConfig, error) {
 	data, err := os.ReadFile(configPath)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var config ProjectConfig
 	if err := yaml.Unmarshal(data, &config); err != nil {
-		return fmt.Errorf("failed to parse YAML: %w", err)
+		return nil, fmt.Errorf("failed to parse YAML: %w", err)
 	}
 
-	currentConfig = &config
-	return nil
+	return &config, nil
 }
 
-// GetConfig returns the loaded project configuration
-func GetConfig() *ProjectConfig {
-	return currentConfig
-}
+// resolveComposeFiles returns absolute paths to the compose files for a project.
+// Explicitly configured files must all exist; otherwise the first standard default
+// name is used, together with its override file if present (mirroring docker compose)
+func resolveComposeFiles(dir string, cfg ComposeConfig) ([]string, error) {
+	if len(cfg.Files) > 0 {
+		var files []string
+		for _, name := range cfg.Files {
+			path := name
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(dir, name)
+			}
+			if !fileExists(path) {
+				return nil, fmt.Errorf("compose file %s does not exist", name)
+			}
+			files = append(files, path)
+		}
+		return files, nil
+	}
 
-// MustFindProjectRoot finds the project root or exits with a helpful error message
-func MustFindProjectRoot() string {
-	projectRoot, err := FindProjectRoot()
-	if err != nil {
-		fmt.Println()
-		fmt.Println("\033[31m✗\033[0m Could not find project root")
-		fmt.Println("\033[36mℹ\033[0m Run this command from inside a project with .musing.yaml")
-		os.Exit(1)
+	for _, name := range defaultComposeFiles {
+		path := filepath.Join(dir, name)
+		if !fileExists(path) {
+			continue
+		}
+
+		files := []string{path}
+		ext := filepath.Ext(name)
+		override := filepath.Join(dir, strings.TrimSuffix(name, ext)+".override"+ext)
+		if fileExists(override) {
+			files = append(files, override)
+		}
+		return files, nil
 	}
-	return projectRoot
+
+	return nil, fmt.Errorf("no compose file (looked for %s)", strings.Join(defaultComposeFiles, ", "))
 }
 
-// hasComposeFile checks if directory contains compose.yaml
-func hasComposeFile(dir string) bool {
-	composePath := filepath.Join(dir, "compose.yaml")
-	_, err := os.Stat(composePath)
-	return err == nil
+// fileExists reports whether path exists and is not a directory
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
 }
 
-// GetAPIRepos returns paths to expected API repositories
-// Dynamically discovers repos relative to project root based on config
-func GetAPIRepos() []string {
-	if currentConfig == nil {
-		return []string{}
+// resolvePath expands ~ and makes path absolute relative to base
+func resolvePath(base, path string) string {
+	path = expandHome(path)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(base, path)
 	}
+	return filepath.Clean(path)
+}
 
-	projectRoot, err := FindProjectRoot()
-	if err != nil {
-		return []string{}
+// resolveRepoURL resolves local repo paths (e.g. bare repos) relative to base,
+// leaving remote URLs such as https://... or git@host:org/repo.git untouched
+func resolveRepoURL(base, url string) string {
+	if url == "" || strings.Contains(url, "://") {
+		return url
 	}
+	colon := strings.Index(url, ":")
+	slash := strings.Index(url, "/")
+	if colon >= 0 && (slash < 0 || colon < slash) {
+		return url // scp-like syntax
+	}
+	return resolvePath(base, url)
+}
 
-	parentDir := filepath.Dir(projectRoot)
-	var repos []string
-
-	for _, svc := range currentConfig.Services {
-		if svc.Type == "api" {
-			repoPath := filepath.Join(parentDir, svc.Name)
-			repos = append(repos, repoPath)
+// expandHome expands a leading ~/ to the user's home directory
+func expandHome(path string) string {
+	if strings.HasPrefix(path, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(home, path[2:])
 		}
 	}
-
-	return repos
+	return path
 }
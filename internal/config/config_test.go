@@ -0,0 +1,129 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// TestResolveComposeFiles tests compose file discovery and explicit configuration
+func TestResolveComposeFiles(t *testing.T) {
+	tests := []struct {
+		name     string
+		present  []string
+		cfg      ComposeConfig
+		expected []string
+		wantErr  bool
+	}{
+		{
+			name:     "compose.yaml default",
+			present:  []string{"compose.yaml"},
+			expected: []string{"compose.yaml"},
+		},
+		{
+			name:     "docker-compose.yml default",
+			present:  []string{"docker-compose.yml"},
+			expected: []string{"docker-compose.yml"},
+		},
+		{
+			name:     "default with override",
+			present:  []string{"compose.yml", "compose.override.yml"},
+			expected: []string{"compose.yml", "compose.override.yml"},
+		},
+		{
+			name:     "prefers compose.yaml over docker-compose.yml",
+			present:  []string{"docker-compose.yml", "compose.yaml"},
+			expected: []string{"compose.yaml"},
+		},
+		{
+			name:     "explicit files",
+			present:  []string{"base.yml", "dev.yml"},
+			cfg:      ComposeConfig{Files: []string{"base.yml", "dev.yml"}},
+			expected: []string{"base.yml", "dev.yml"},
+		},
+		{
+			name:    "explicit file missing",
+			present: []string{"compose.yaml"},
+			cfg:     ComposeConfig{Files: []string{"missing.yml"}},
+			wantErr: true,
+		},
+		{
+			name:    "no compose file",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for _, name := range tt.present {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte("services: {}\n"), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			files, err := resolveComposeFiles(dir, tt.cfg)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("resolveComposeFiles() = %v, want error", files)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveComposeFiles() error = %v", err)
+			}
+
+			var expected []string
+			for _, name := range tt.expected {
+				expected = append(expected, filepath.Join(dir, name))
+			}
+			if !reflect.DeepEqual(files, expected) {
+				t.Errorf("resolveComposeFiles() = %v, want %v", files, expected)
+			}
+		})
+	}
+}
+
+// TestLoadWithRegisteredProject tests --project resolution through the registry
+func TestLoadWithRegisteredProject(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(ProjectEnvVar, "")
+
+	projectDir := t.TempDir()
+	for name, content := range map[string]string{
+		ConfigFileName: "services:\n  - name: api\n    port: 8080\n    type: api\n",
+		"compose.yaml": "services: {}\n",
+	} {
+		if err := os.WriteFile(filepath.Join(projectDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	registry, err := LoadRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Add("blog", projectDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	project, err := Load(SearchOptions{Project: "blog"})
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if project.Root != projectDir {
+		t.Errorf("Load().Root = %q, want %q", project.Root, projectDir)
+	}
+	if len(project.Config.Services) != 1 {
+		t.Errorf("Load().Config = %+v, want one service", project.Config)
+	}
+
+	if _, err := Load(SearchOptions{Project: "unknown"}); err == nil {
+		t.Error("Load() with unknown project succeeded, want error")
+	}
+}
@@ -0,0 +1,200 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ProjectEnvVar selects the project when no --project flag is given
+const ProjectEnvVar = "MUSING_PROJECT"
+
+// Project is a loaded musing project: its root directory, configuration and resolved paths
+type Project struct {
+	Root         string         // Directory containing the config file
+	ConfigPath   string         // Path to the config file (empty for in-memory projects)
+	Config       *ProjectConfig // Parsed configuration
+	ComposeFiles []string       // Absolute paths to the compose files
+}
+
+// SearchOptions overrides how Load locates the project
+type SearchOptions struct {
+	Project    string // Project directory or registered project name (--project)
+	ConfigFile string // Explicit path to a config file (--config)
+}
+
+// NewProject creates a project from an in-memory configuration rooted at root.
+// Compose files are resolved if present but not required
+func NewProject(root string, cfg *ProjectConfig) *Project {
+	p := &Project{Root: root, Config: cfg}
+	if files, err := resolveComposeFiles(root, cfg.Compose); err == nil {
+		p.ComposeFiles = files
+	}
+	return p
+}
+
+// Load locates the project and loads its configuration. In order of precedence:
+// an explicit config file, a project directory or registered name (from --project or
+// MUSING_PROJECT), a .musing.yaml found searching upward from CWD, and finally the
+// project selected with 'musing projects use'
+func Load(opts SearchOptions) (*Project, error) {
+	if opts.ConfigFile != "" {
+		configPath, err := filepath.Abs(expandHome(opts.ConfigFile))
+		if err != nil {
+			return nil, err
+		}
+		return loadProject(configPath)
+	}
+
+	project := opts.Project
+	if project == "" {
+		project = os.Getenv(ProjectEnvVar)
+	}
+	if project != "" {
+		dir, err := resolveProjectDir(project)
+		if err != nil {
+			return nil, err
+		}
+		return loadProject(filepath.Join(dir, ConfigFileName))
+	}
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current directory: %w", err)
+	}
+
+	// Search upward from current directory
+	dir := currentDir
+	for {
+		// Check if this directory contains .musing.yaml file
+		musingPath := filepath.Join(dir, ConfigFileName)
+		if fileExists(musingPath) {
+			return loadProject(musingPath)
+		}
+
+		// Move to parent directory
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			// Reached filesystem root
+			break
+		}
+		dir = parent
+	}
+
+	// Fall back to the project selected with 'musing projects use'
+	if registry, err := LoadRegistry(); err == nil && registry.Current != "" {
+		if dir, ok := registry.Projects[registry.Current]; ok {
+			return loadProject(filepath.Join(dir, ConfigFileName))
+		}
+	}
+
+	return nil, fmt.Errorf("no %s file found (searched upward from %s)", ConfigFileName, currentDir)
+}
+
+// resolveProjectDir maps a registered project name or a directory path to a project directory
+func resolveProjectDir(project string) (string, error) {
+	if registry, err := LoadRegistry(); err == nil {
+		if dir, ok := registry.Projects[project]; ok {
+			return dir, nil
+		}
+	}
+
+	dir, err := filepath.Abs(expandHome(project))
+	if err != nil {
+		return "", err
+	}
+	if !fileExists(filepath.Join(dir, ConfigFileName)) {
+		return "", fmt.Errorf("project %q is neither a registered project nor a directory containing %s", project, ConfigFileName)
+	}
+	return dir, nil
+}
+
+// loadProject loads the config file at configPath and resolves its compose files
+func loadProject(configPath string) (*Project, error) {
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config from %s: %w", configPath, err)
+	}
+
+	// Verify compose file(s) exist
+	dir := filepath.Dir(configPath)
+	files, err := resolveComposeFiles(dir, cfg.Compose)
+	if err != nil {
+		return nil, fmt.Errorf("found %s at %s but %w", filepath.Base(configPath), dir, err)
+	}
+
+	return &Project{
+		Root:         dir,
+		ConfigPath:   configPath,
+		Config:       cfg,
+		ComposeFiles: files,
+	}, nil
+}
+
+// DataDir returns the absolute path of the database data directory
+func (p *Project) DataDir() string {
+	return resolvePath(p.Root, p.Config.Database.DataDir)
+}
+
+// StateDir returns the project's local state directory (.musing/), which should stay out of git
+func (p *Project) StateDir() string {
+	return filepath.Join(p.Root, ".musing")
+}
+
+// HealthHistoryPath returns the file health check history is recorded in
+func (p *Project) HealthHistoryPath() string {
+	return filepath.Join(p.StateDir(), "health.db")
+}
+
+// TimelinePath returns the file monitor events and deploys are recorded in
+func (p *Project) TimelinePath() string {
+	return filepath.Join(p.StateDir(), "timeline.db")
+}
+
+// SecretsPath returns the encrypted secrets file for an environment
+func (p *Project) SecretsPath(env string) string {
+	return filepath.Join(p.StateDir(), "secrets", env+".enc")
+}
+
+// SecretsKeyFile returns the configured secrets key file, or empty if passphrases are used
+func (p *Project) SecretsKeyFile() string {
+	if p.Config.Secrets.KeyFile == "" {
+		return ""
+	}
+	return resolvePath(p.Root, p.Config.Secrets.KeyFile)
+}
+
+// EnvFilePath returns the .env file that secrets are materialised into
+func (p *Project) EnvFilePath() string {
+	if p.Config.Secrets.EnvFile == "" {
+		return filepath.Join(p.Root, ".env")
+	}
+	return resolvePath(p.Root, p.Config.Secrets.EnvFile)
+}
+
+// Repos returns the repositories backing the configured services.
+// API services default to ../<service name>; other services are included
+// only when they declare a repo explicitly
+func (p *Project) Repos() []Repo {
+	var repos []Repo
+	for _, svc := range p.Config.Services {
+		if svc.Repo == nil && svc.Type != "api" {
+			continue
+		}
+
+		repo := Repo{
+			Name: svc.Name,
+			Path: filepath.Join(filepath.Dir(p.Root), svc.Name),
+		}
+		if svc.Repo != nil {
+			if svc.Repo.Path != "" {
+				repo.Path = resolvePath(p.Root, svc.Repo.Path)
+			}
+			repo.URL = resolveRepoURL(p.Root, svc.Repo.URL)
+			repo.Branch = svc.Repo.Branch
+		}
+		repos = append(repos, repo)
+	}
+
+	return repos
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Registry is the user-level list of named projects (~/.config/musing/projects.yaml)
+type Registry struct {
+	Current  string            `yaml:"current,omitempty"` // Project selected with 'musing projects use'
+	Projects map[string]string `yaml:"projects"`          // Project name -> project directory
+}
+
+// RegistryPath returns the location of the user-level project registry
+func RegistryPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate user config directory: %w", err)
+	}
+	return filepath.Join(configDir, "musing", "projects.yaml"), nil
+}
+
+// LoadRegistry reads the project registry, returning an empty registry if none exists yet
+func LoadRegistry() (*Registry, error) {
+	registry := &Registry{Projects: map[string]string{}}
+
+	path, err := RegistryPath()
+	if err != nil {
+		return registry, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return registry, nil
+	}
+	if err != nil {
+		return registry, err
+	}
+
+	if err := yaml.Unmarshal(data, registry); err != nil {
+		return registry, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if registry.Projects == nil {
+		registry.Projects = map[string]string{}
+	}
+	return registry, nil
+}
+
+// Save writes the registry back to disk
+func (r *Registry) Save() error {
+	path, err := RegistryPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Add registers dir under name, verifying it contains a .musing.yaml
+func (r *Registry) Add(name, dir string) error {
+	absDir, err := filepath.Abs(expandHome(dir))
+	if err != nil {
+		return err
+	}
+	if !fileExists(filepath.Join(absDir, ConfigFileName)) {
+		return fmt.Errorf("%s does not contain %s", absDir, ConfigFileName)
+	}
+
+	r.Projects[name] = absDir
+	return nil
+}
+
+// Remove unregisters name, clearing the current selection if it pointed at it
+func (r *Registry) Remove(name string) error {
+	if _, ok := r.Projects[name]; !ok {
+		return fmt.Errorf("project %q is not registered", name)
+	}
+
+	delete(r.Projects, name)
+	if r.Current == name {
+		r.Current = ""
+	}
+	return nil
+}
+
+// Use selects name as the fallback project when musing runs outside a project
+func (r *Registry) Use(name string) error {
+	if _, ok := r.Projects[name]; !ok {
+		return fmt.Errorf("project %q is not registered", name)
+	}
+
+	r.Current = name
+	return nil
+}
+
+// Names returns the registered project names in sorted order
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.Projects))
+	for name := range r.Projects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
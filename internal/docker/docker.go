@@ -1,8 +1,12 @@
 package docker
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"time"
@@ -10,7 +14,12 @@ import (
 
 // CheckRunning checks if Docker daemon is running
 func CheckRunning() error {
-	cmd := exec.Command("docker", "info")
+	return Ping(context.Background())
+}
+
+// Ping checks if Docker daemon is running, giving up when ctx is done
+func Ping(ctx context.Context) error {
+	cmd := exec.CommandContext(ctx, "docker", "info")
 	cmd.Stdout = nil
 	cmd.Stderr = nil
 
@@ -112,44 +121,157 @@ func waitForReady(timeout time.Duration) error {
 	}
 }
 
+// Compose identifies the compose project that docker compose commands operate on
+type Compose struct {
+	Files    []string // Compose files, passed with -f
+	Project  string   // Optional project name, passed with -p
+	EnvFiles []string // Env files for variable interpolation, passed with --env-file
+}
+
+// Args builds the full docker argument list for a compose subcommand,
+// including the configured compose files, project name and env files
+func (c Compose) Args(args ...string) []string {
+	composeArgs := []string{"compose"}
+	for _, file := range c.Files {
+		composeArgs = append(composeArgs, "-f", file)
+	}
+	for _, file := range c.EnvFiles {
+		composeArgs = append(composeArgs, "--env-file", file)
+	}
+	if c.Project != "" {
+		composeArgs = append(composeArgs, "-p", c.Project)
+	}
+	return append(composeArgs, args...)
+}
+
+// Command returns an exec.Cmd running docker compose with the given subcommand
+func (c Compose) Command(args ...string) *exec.Cmd {
+	return exec.Command("docker", c.Args(args...)...)
+}
+
 // ComposeUp starts services with docker compose
-func ComposeUp() error {
-	cmd := exec.Command("docker", "compose", "up", "-d")
+func ComposeUp(c Compose) error {
+	cmd := c.Command("up", "-d")
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
 
 // ComposeDown stops all services
-func ComposeDown() error {
-	cmd := exec.Command("docker", "compose", "down")
+func ComposeDown(c Compose) error {
+	cmd := c.Command("down")
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
 
 // ComposeBuild builds images
-func ComposeBuild(noCache bool) error {
-	args := []string{"compose", "build"}
+func ComposeBuild(c Compose, noCache bool) error {
+	args := []string{"build"}
 	if noCache {
 		args = append(args, "--no-cache")
 	}
 
-	cmd := exec.Command("docker", args...)
+	cmd := c.Command(args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
 
 // ComposeLogs follows logs from all services
-func ComposeLogs(follow bool) error {
-	args := []string{"compose", "logs"}
+func ComposeLogs(c Compose, follow bool) error {
+	args := []string{"logs"}
 	if follow {
 		args = append(args, "-f")
 	}
 
-	cmd := exec.Command("docker", args...)
+	cmd := c.Command(args...)
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 	return cmd.Run()
 }
+
+// StreamLogs follows the logs of a service (all services when service is empty), sending
+// each line on the returned channel. The channel is closed when ctx is cancelled or docker exits
+func StreamLogs(ctx context.Context, c Compose, service string, tail int) (<-chan string, error) {
+	args := []string{"logs", "--follow", "--no-color", "--tail", fmt.Sprint(tail)}
+	if service != "" {
+		args = append(args, "--no-log-prefix", service)
+	}
+
+	reader, writer := io.Pipe()
+	cmd := exec.CommandContext(ctx, "docker", c.Args(args...)...)
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("docker compose logs failed: %w", err)
+	}
+
+	go func() {
+		writer.CloseWithError(cmd.Wait())
+	}()
+
+	lines := make(chan string, 256)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(reader)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-ctx.Done():
+				reader.Close()
+				return
+			}
+		}
+	}()
+
+	return lines, nil
+}
+
+// Container is a compose container as reported by docker compose ps
+type Container struct {
+	Name     string `json:"Name"`
+	Service  string `json:"Service"`
+	State    string `json:"State"`  // e.g. running, exited, restarting
+	Health   string `json:"Health"` // healthy, unhealthy, starting, or empty without a healthcheck
+	ExitCode int    `json:"ExitCode"`
+}
+
+// ComposePS lists the project's containers, including stopped ones
+func ComposePS(c Compose) ([]Container, error) {
+	output, err := c.Command("ps", "--all", "--format", "json").Output()
+	if err != nil {
+		return nil, fmt.Errorf("docker compose ps failed: %w", err)
+	}
+	return ParseComposePS(output)
+}
+
+// ParseComposePS parses docker compose ps JSON output (local, or run on a server): a JSON
+// array from older releases, or one object per line from newer ones
+func ParseComposePS(output []byte) ([]Container, error) {
+	output = bytes.TrimSpace(output)
+	if len(output) == 0 {
+		return nil, nil
+	}
+
+	var containers []Container
+	if output[0] == '[' {
+		if err := json.Unmarshal(output, &containers); err != nil {
+			return nil, fmt.Errorf("invalid docker compose ps output: %w", err)
+		}
+		return containers, nil
+	}
+
+	for _, line := range bytes.Split(output, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var container Container
+		if err := json.Unmarshal(line, &container); err != nil {
+			return nil, fmt.Errorf("invalid docker compose ps output: %w", err)
+		}
+		containers = append(containers, container)
+	}
+	return containers, nil
+}
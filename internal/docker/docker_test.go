@@ -0,0 +1,51 @@
+package docker
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestParseComposePS tests both the JSON array and JSON lines output formats
+func TestParseComposePS(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+	}{
+		{
+			name:   "json lines",
+			output: `{"Name":"proj-api-1","Service":"api","State":"running","Health":"healthy"}` + "\n" + `{"Name":"proj-db-1","Service":"db","State":"exited","ExitCode":1}`,
+		},
+		{
+			name:   "json array",
+			output: `[{"Name":"proj-api-1","Service":"api","State":"running","Health":"healthy"},{"Name":"proj-db-1","Service":"db","State":"exited","ExitCode":1}]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			containers, err := ParseComposePS([]byte(tt.output))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(containers) != 2 {
+				t.Fatalf("got %d containers, expected 2", len(containers))
+			}
+			if c := containers[0]; c.Service != "api" || c.State != "running" || c.Health != "healthy" {
+				t.Errorf("first container = %+v", c)
+			}
+			if c := containers[1]; c.Name != "proj-db-1" || c.State != "exited" || c.ExitCode != 1 {
+				t.Errorf("second container = %+v", c)
+			}
+		})
+	}
+}
+
+// TestComposeArgs tests compose files, project and env files come before the subcommand
+func TestComposeArgs(t *testing.T) {
+	c := Compose{Files: []string{"compose.yml", "compose.dev.yml"}, Project: "musing", EnvFiles: []string{"/proj/secrets.env"}}
+
+	expected := []string{"compose", "-f", "compose.yml", "-f", "compose.dev.yml", "--env-file", "/proj/secrets.env", "-p", "musing", "up", "-d"}
+	if args := c.Args("up", "-d"); !reflect.DeepEqual(args, expected) {
+		t.Errorf("Args() = %v, expected %v", args, expected)
+	}
+}
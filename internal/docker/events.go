@@ -0,0 +1,65 @@
+package docker
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"time"
+)
+
+// ComposeEvent is a container event reported by docker compose events
+type ComposeEvent struct {
+	Time       time.Time         `json:"time"`
+	Service    string            `json:"service"`
+	Container  string            `json:"id"`
+	Action     string            `json:"action"` // e.g. start, die, restart, oom, "health_status: unhealthy"
+	Attributes map[string]string `json:"attributes"`
+}
+
+// StreamEvents follows the project's container events, sending each on the returned
+// channel. The channel is closed when ctx is cancelled or docker exits
+func StreamEvents(ctx context.Context, c Compose) (<-chan ComposeEvent, error) {
+	cmd := exec.CommandContext(ctx, "docker", c.Args("events", "--json")...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("docker compose events failed: %w", err)
+	}
+
+	events := make(chan ComposeEvent, 64)
+	go func() {
+		defer close(events)
+		defer cmd.Wait()
+
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			event, ok := parseComposeEvent(scanner.Bytes())
+			if !ok {
+				continue
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// parseComposeEvent parses one line of docker compose events --json output
+func parseComposeEvent(line []byte) (ComposeEvent, bool) {
+	var event ComposeEvent
+	if err := json.Unmarshal(line, &event); err != nil || event.Action == "" {
+		return ComposeEvent{}, false
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	return event, true
+}
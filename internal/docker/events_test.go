@@ -0,0 +1,28 @@
+package docker
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParseComposeEvent tests parsing docker compose events --json lines
+func TestParseComposeEvent(t *testing.T) {
+	line := `{"action":"die","attributes":{"exitCode":"137","image":"api:dev","name":"proj-api-1"},"id":"4f2a","service":"api","time":"2026-01-01T10:00:00.123456789Z","type":"container"}`
+
+	event, ok := parseComposeEvent([]byte(line))
+	if !ok {
+		t.Fatal("expected the event to parse")
+	}
+	if event.Service != "api" || event.Action != "die" || event.Attributes["exitCode"] != "137" {
+		t.Errorf("event = %+v", event)
+	}
+	if expected := time.Date(2026, 1, 1, 10, 0, 0, 123456789, time.UTC); !event.Time.Equal(expected) {
+		t.Errorf("Time = %v, expected %v", event.Time, expected)
+	}
+
+	for _, line := range []string{"", "not json", `{"service":"api"}`} {
+		if _, ok := parseComposeEvent([]byte(line)); ok {
+			t.Errorf("parseComposeEvent(%q) succeeded, expected it to be skipped", line)
+		}
+	}
+}
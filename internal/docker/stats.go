@@ -0,0 +1,187 @@
+package docker
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Stats is a container's resource usage and lifecycle state
+type Stats struct {
+	Name       string
+	CPUPercent float64
+	MemUsage   uint64 // Bytes
+	MemLimit   uint64 // Bytes
+	Restarts   int
+	StartedAt  time.Time
+	Health     string // healthy, unhealthy, starting, or empty without a healthcheck
+}
+
+// statsLine is one line of docker stats --format json
+type statsLine struct {
+	Name     string `json:"Name"`
+	CPUPerc  string `json:"CPUPerc"`
+	MemUsage string `json:"MemUsage"`
+}
+
+// inspectEntry holds the docker inspect fields used by ContainerStats
+type inspectEntry struct {
+	Name         string `json:"Name"`
+	RestartCount int    `json:"RestartCount"`
+	State        struct {
+		StartedAt time.Time `json:"StartedAt"`
+		Health    *struct {
+			Status string `json:"Status"`
+		} `json:"Health"`
+	} `json:"State"`
+}
+
+// ContainerStats samples CPU and memory usage, restart counts and uptime for running
+// containers. Containers that have gone since they were listed are left out
+func ContainerStats(names []string) (map[string]Stats, error) {
+	if len(names) == 0 {
+		return map[string]Stats{}, nil
+	}
+
+	statsArgs := func(names []string) []string {
+		return append([]string{"stats", "--no-stream", "--format", "json"}, names...)
+	}
+	output, err := exec.Command("docker", statsArgs(names)...).Output()
+	if missing := missingContainers(err); len(missing) > 0 {
+		// docker stats gives up on every container when one is missing, so try without it
+		names = slices.DeleteFunc(slices.Clone(names), func(name string) bool { return slices.Contains(missing, name) })
+		if len(names) == 0 {
+			return map[string]Stats{}, nil
+		}
+		output, err = exec.Command("docker", statsArgs(names)...).Output()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("docker stats failed: %w", err)
+	}
+	stats, err := parseStats(output)
+	if err != nil {
+		return nil, err
+	}
+
+	// docker inspect still prints the containers it found when one is missing
+	output, err = exec.Command("docker", append([]string{"inspect"}, names...)...).Output()
+	if err != nil && len(missingContainers(err)) == 0 {
+		return nil, fmt.Errorf("docker inspect failed: %w", err)
+	}
+	if len(bytes.TrimSpace(output)) == 0 {
+		return stats, nil // Every container went between the two commands
+	}
+	if err := mergeInspect(stats, output); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// missingContainers returns the containers a failed docker command reported as missing,
+// e.g. "Error response from daemon: No such container: proj-api-1"
+func missingContainers(err error) []string {
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return nil
+	}
+
+	var missing []string
+	for _, line := range strings.Split(string(exitErr.Stderr), "\n") {
+		for _, prefix := range []string{"No such container: ", "No such object: "} {
+			if _, name, ok := strings.Cut(line, prefix); ok {
+				missing = append(missing, strings.TrimSpace(name))
+			}
+		}
+	}
+	return missing
+}
+
+// parseStats parses docker stats JSON lines into stats keyed by container name
+func parseStats(output []byte) (map[string]Stats, error) {
+	stats := map[string]Stats{}
+	for _, line := range bytes.Split(bytes.TrimSpace(output), []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var entry statsLine
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, fmt.Errorf("invalid docker stats output: %w", err)
+		}
+
+		s := Stats{Name: entry.Name}
+		s.CPUPercent, _ = strconv.ParseFloat(strings.TrimSuffix(entry.CPUPerc, "%"), 64)
+		if usage, limit, ok := strings.Cut(entry.MemUsage, "/"); ok {
+			s.MemUsage = parseSize(usage)
+			s.MemLimit = parseSize(limit)
+		}
+		stats[s.Name] = s
+	}
+	return stats, nil
+}
+
+// mergeInspect adds restart counts, start times and health from docker inspect output
+func mergeInspect(stats map[string]Stats, output []byte) error {
+	var entries []inspectEntry
+	if err := json.Unmarshal(output, &entries); err != nil {
+		return fmt.Errorf("invalid docker inspect output: %w", err)
+	}
+	for _, entry := range entries {
+		name := strings.TrimPrefix(entry.Name, "/")
+		s, ok := stats[name]
+		if !ok {
+			continue
+		}
+		s.Restarts = entry.RestartCount
+		s.StartedAt = entry.State.StartedAt
+		if entry.State.Health != nil {
+			s.Health = entry.State.Health.Status
+		}
+		stats[name] = s
+	}
+	return nil
+}
+
+// sizeUnits are the suffixes docker uses for sizes, longest first so "MiB" wins over "B"
+var sizeUnits = []struct {
+	suffix string
+	bytes  float64
+}{
+	{"KiB", 1 << 10}, {"MiB", 1 << 20}, {"GiB", 1 << 30}, {"TiB", 1 << 40},
+	{"kB", 1e3}, {"KB", 1e3}, {"MB", 1e6}, {"GB", 1e9}, {"TB", 1e12},
+	{"B", 1},
+}
+
+// parseSize parses a size like "12.5MiB" into bytes (0 if it can't be parsed)
+func parseSize(s string) uint64 {
+	s = strings.TrimSpace(s)
+	for _, unit := range sizeUnits {
+		if number, ok := strings.CutSuffix(s, unit.suffix); ok {
+			value, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+			if err != nil {
+				return 0
+			}
+			return uint64(value * unit.bytes)
+		}
+	}
+	return 0
+}
+
+// FormatBytes formats a byte count with a binary unit, e.g. "118.2MiB"
+func FormatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
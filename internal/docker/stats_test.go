@@ -0,0 +1,66 @@
+package docker
+
+import (
+	"os/exec"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestParseStats tests parsing docker stats and inspect output into Stats
+func TestParseStats(t *testing.T) {
+	output := `{"Name":"proj-api-1","CPUPerc":"12.50%","MemUsage":"118.2MiB / 1.944GiB"}` + "\n" +
+		`{"Name":"proj-db-1","CPUPerc":"0.31%","MemUsage":"512kB / 2GB"}`
+
+	stats, err := parseStats([]byte(output))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	inspect := `[{"Name":"/proj-api-1","RestartCount":3,"State":{"StartedAt":"2024-05-01T10:00:00Z","Health":{"Status":"healthy"}}},
+		{"Name":"/proj-db-1","RestartCount":0,"State":{"StartedAt":"2024-05-01T09:00:00Z"}}]`
+	if err := mergeInspect(stats, []byte(inspect)); err != nil {
+		t.Fatal(err)
+	}
+
+	api := stats["proj-api-1"]
+	if api.CPUPercent != 12.5 || FormatBytes(api.MemUsage) != "118.2MiB" || FormatBytes(api.MemLimit) != "1.9GiB" {
+		t.Errorf("api usage = %+v", api)
+	}
+	if api.Restarts != 3 || api.Health != "healthy" || !api.StartedAt.Equal(time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("api state = %+v", api)
+	}
+
+	db := stats["proj-db-1"]
+	if db.MemUsage != 512000 || db.MemLimit != 2e9 || db.Health != "" {
+		t.Errorf("db = %+v", db)
+	}
+}
+
+// TestMissingContainers tests picking out containers docker reported as gone
+func TestMissingContainers(t *testing.T) {
+	err := &exec.ExitError{Stderr: []byte("Error response from daemon: No such container: proj-api-1\nError: No such object: proj-db-1\n")}
+	if missing := missingContainers(err); !reflect.DeepEqual(missing, []string{"proj-api-1", "proj-db-1"}) {
+		t.Errorf("missingContainers() = %v", missing)
+	}
+
+	other := &exec.ExitError{Stderr: []byte("Cannot connect to the Docker daemon\n")}
+	if missing := missingContainers(other); len(missing) != 0 {
+		t.Errorf("missingContainers() = %v for an unrelated failure", missing)
+	}
+}
+
+// TestFormatBytes tests binary size formatting
+func TestFormatBytes(t *testing.T) {
+	tests := map[uint64]string{
+		512:               "512B",
+		2048:              "2.0KiB",
+		118 * (1 << 20):   "118.0MiB",
+		3 * (1 << 30) / 2: "1.5GiB",
+	}
+	for n, expected := range tests {
+		if got := FormatBytes(n); got != expected {
+			t.Errorf("FormatBytes(%d) = %q, expected %q", n, got, expected)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// Status represents the working state of a git repository
+type Status struct {
+	Branch      string // Current branch ("(detached)" when HEAD is detached)
+	Upstream    string // Tracking branch, empty if none
+	Dirty       bool   // Uncommitted or untracked changes present
+	Ahead       int    // Commits ahead of upstream
+	Behind      int    // Commits behind upstream
+	HasUpstream bool
+}
+
+// IsRepo checks if path is a git working tree
+func IsRepo(path string) bool {
+	info, err := os.Stat(path)
+	if err != nil || !info.IsDir() {
+		return false
+	}
+	return exec.Command("git", "-C", path, "rev-parse", "--is-inside-work-tree").Run() == nil
+}
+
+// CloneArgs returns the git arguments used to clone url into path
+func CloneArgs(url, path, branch string) []string {
+	args := []string{"clone"}
+	if branch != "" {
+		args = append(args, "--branch", branch)
+	}
+	return append(args, url, path)
+}
+
+// Fetch updates remote tracking branches without touching the working tree
+func Fetch(path string) error {
+	output, err := exec.Command("git", "-C", path, "fetch", "--quiet").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git fetch failed: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// Pull fast-forwards the current branch from its upstream and returns git's summary
+func Pull(path string) (string, error) {
+	output, err := exec.Command("git", "-C", path, "pull", "--ff-only").CombinedOutput()
+	summary := strings.TrimSpace(string(output))
+	if err != nil {
+		return summary, fmt.Errorf("git pull failed: %s", summary)
+	}
+	return summary, nil
+}
+
+// GetStatus returns branch, dirty and ahead/behind state for the repo at path
+func GetStatus(path string) (Status, error) {
+	output, err := exec.Command("git", "-C", path, "status", "--porcelain=v2", "--branch").Output()
+	if err != nil {
+		return Status{}, fmt.Errorf("git status failed: %w", err)
+	}
+	return parseStatus(string(output)), nil
+}
+
+// parseStatus parses `git status --porcelain=v2 --branch` output
+func parseStatus(output string) Status {
+	var status Status
+
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+
+		if !strings.HasPrefix(line, "# ") {
+			// Any entry line means changed, unmerged or untracked files
+			status.Dirty = true
+			continue
+		}
+
+		fields := strings.Fields(line[2:])
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch fields[0] {
+		case "branch.head":
+			status.Branch = fields[1]
+		case "branch.upstream":
+			status.Upstream = fields[1]
+			status.HasUpstream = true
+		case "branch.ab":
+			if len(fields) >= 3 {
+				status.Ahead, _ = strconv.Atoi(strings.TrimPrefix(fields[1], "+"))
+				status.Behind, _ = strconv.Atoi(strings.TrimPrefix(fields[2], "-"))
+			}
+		}
+	}
+
+	return status
+}
@@ -0,0 +1,37 @@
+package git
+
+import "testing"
+
+// TestParseStatus tests parsing of porcelain v2 status output
+func TestParseStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		output   string
+		expected Status
+	}{
+		{
+			name:     "clean and up to date",
+			output:   "# branch.oid abc123\n# branch.head main\n# branch.upstream origin/main\n# branch.ab +0 -0\n",
+			expected: Status{Branch: "main", Upstream: "origin/main", HasUpstream: true},
+		},
+		{
+			name:     "dirty, ahead and behind",
+			output:   "# branch.head feature\n# branch.upstream origin/feature\n# branch.ab +2 -3\n1 .M N... 100644 100644 100644 abc abc main.go\n? notes.txt\n",
+			expected: Status{Branch: "feature", Upstream: "origin/feature", HasUpstream: true, Dirty: true, Ahead: 2, Behind: 3},
+		},
+		{
+			name:     "no upstream",
+			output:   "# branch.oid abc123\n# branch.head main\n",
+			expected: Status{Branch: "main"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := parseStatus(tt.output)
+			if result != tt.expected {
+				t.Errorf("parseStatus() = %+v, want %+v", result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Check is a named health check run by a Checker
+type Check struct {
+	Name    string
+	Timeout time.Duration // Per-check limit, used instead of the Checker's deadline
+	Run     func(ctx context.Context) Result
+}
+
+// Result is the outcome of a Check
+type Result struct {
+	Index   int    // Position of the check in the slice passed to the Checker
+	Name    string // Name of the check
+	Status  string // StatusRunning, StatusDegraded or StatusDown
+	Latency time.Duration
+	Detail  string // Extra context for display, e.g. a database version
+	Error   error
+}
+
+// Checker runs health checks concurrently on a bounded pool of workers.
+// Checks still running when their own timeout, or without one the shared deadline,
+// passes are reported down
+type Checker struct {
+	Workers  int           // Maximum concurrent checks (default: 8)
+	Deadline time.Duration // Limit for the whole run (default: 5s)
+}
+
+// Stream starts the checks and returns a channel that receives each result as it
+// completes. The channel is closed once every check has reported
+func (c Checker) Stream(ctx context.Context, checks []Check) <-chan Result {
+	workers := c.Workers
+	if workers <= 0 {
+		workers = 8
+	}
+	deadline := c.Deadline
+	if deadline <= 0 {
+		deadline = 5 * time.Second
+	}
+
+	results := make(chan Result, len(checks))
+	jobs := make(chan int)
+
+	round, cancel := context.WithTimeout(ctx, deadline)
+
+	var wg sync.WaitGroup
+	for range min(workers, len(checks)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				check := checks[i]
+				if check.Timeout <= 0 {
+					results <- runCheck(round, i, check)
+					continue
+				}
+				// A check with its own timeout, like a slow remote endpoint, may outlast the round
+				checkCtx, cancelCheck := context.WithTimeout(ctx, check.Timeout)
+				results <- runCheck(checkCtx, i, check)
+				cancelCheck()
+			}
+		}()
+	}
+
+	go func() {
+		for i := range checks {
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+		cancel()
+		close(results)
+	}()
+
+	return results
+}
+
+// Run runs the checks and returns their results in the order the checks were given
+func (c Checker) Run(ctx context.Context, checks []Check) []Result {
+	results := make([]Result, len(checks))
+	for result := range c.Stream(ctx, checks) {
+		results[result.Index] = result
+	}
+	return results
+}
+
+// runCheck runs a single check. Checks stop their I/O when ctx is done, so one that
+// fails once ctx has expired is reported as timed out
+func runCheck(ctx context.Context, index int, check Check) Result {
+	result := check.Run(ctx)
+	if err := ctx.Err(); err != nil && result.Status == StatusDown {
+		result.Error = fmt.Errorf("check timed out: %w", err)
+	}
+
+	result.Index = index
+	result.Name = check.Name
+	return result
+}
+
+// PortCheck checks that a localhost port accepts TCP connections
+func PortCheck(name string, port int) Check {
+	return Check{
+		Name: name,
+		Run: func(ctx context.Context) Result {
+			status := CheckPort(ctx, port)
+			if !status.Open {
+				return Result{Status: StatusDown, Error: fmt.Errorf("port %d is not accepting connections", port)}
+			}
+			return Result{Status: StatusRunning, Latency: status.Latency}
+		},
+	}
+}
+
+// HTTPHealthCheck runs an HTTP health check
+func HTTPHealthCheck(name string, check HTTPCheck) Check {
+	return Check{
+		Name: name,
+		Run: func(ctx context.Context) Result {
+			status := CheckHTTP(ctx, check)
+			return Result{Status: status.Status, Latency: status.Latency, Error: status.Error}
+		},
+	}
+}
+
+// DatabaseCheck runs a protocol-aware database check on a localhost port
+func DatabaseCheck(name, dbType string, port int) Check {
+	return Check{
+		Name: name,
+		Run: func(ctx context.Context) Result {
+			status := CheckDatabase(ctx, dbType, "localhost", port, 0)
+			return Result{Status: status.Status, Latency: status.RTT, Detail: status.Summary(), Error: status.Error}
+		},
+	}
+}
+
+// RemoteHealthCheck runs a remote check. Its timeout (default: 5s) replaces the
+// Checker's deadline, so a slow endpoint isn't cut short by a short round
+func RemoteHealthCheck(name string, check RemoteCheck) Check {
+	if check.Timeout == 0 {
+		check.Timeout = defaultRemoteTimeout
+	}
+	return Check{
+		Name:    name,
+		Timeout: check.Timeout,
+		Run: func(ctx context.Context) Result {
+			status := CheckRemote(ctx, check)
+			return Result{Status: status.Status, Latency: status.Latency, Detail: status.Summary(), Error: status.Error}
+		},
+	}
+}
+
+// FuncCheck adapts a function reporting success or failure, such as a CLI probe.
+// fn should give up when ctx is done
+func FuncCheck(name string, fn func(ctx context.Context) error) Check {
+	return Check{
+		Name: name,
+		Run: func(ctx context.Context) Result {
+			start := time.Now()
+			if err := fn(ctx); err != nil {
+				return Result{Status: StatusDown, Error: err}
+			}
+			return Result{Status: StatusRunning, Latency: time.Since(start)}
+		},
+	}
+}
+
+// GRPCHealthCheck runs a grpc.health.v1 check
+func GRPCHealthCheck(name string, check GRPCCheck) Check {
+	return Check{
+		Name: name,
+		Run: func(ctx context.Context) Result {
+			status := CheckGRPC(ctx, check)
+			result := Result{Status: status.Status, Latency: status.Latency, Error: status.Error}
+			if status.Serving != ServingServing {
+				result.Detail = status.Serving
+			}
+			return result
+		},
+	}
+}
@@ -0,0 +1,47 @@
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestCheckerRun tests that checks run concurrently, keep their order and time out, and that
+// a check with its own timeout may outlast the shared deadline
+func TestCheckerRun(t *testing.T) {
+	sleep := func(d time.Duration, status string) func(context.Context) Result {
+		return func(ctx context.Context) Result {
+			select {
+			case <-time.After(d):
+				return Result{Status: status}
+			case <-ctx.Done():
+				return Result{Status: StatusDown, Error: ctx.Err()}
+			}
+		}
+	}
+
+	checks := []Check{
+		{Name: "slow", Run: sleep(200*time.Millisecond, StatusRunning)},
+		{Name: "fast", Run: sleep(0, StatusDegraded)},
+		{Name: "hung", Run: sleep(5*time.Second, StatusRunning), Timeout: 100 * time.Millisecond},
+		{Name: "other", Run: sleep(200*time.Millisecond, StatusRunning)},
+		{Name: "late", Run: sleep(400*time.Millisecond, StatusRunning)},
+		{Name: "patient", Run: sleep(400*time.Millisecond, StatusRunning), Timeout: time.Second},
+	}
+
+	start := time.Now()
+	results := Checker{Workers: 6, Deadline: 300 * time.Millisecond}.Run(context.Background(), checks)
+	if elapsed := time.Since(start); elapsed > 700*time.Millisecond {
+		t.Errorf("checks took %v, expected them to run concurrently", elapsed)
+	}
+
+	expected := []string{StatusRunning, StatusDegraded, StatusDown, StatusRunning, StatusDown, StatusRunning}
+	for i, result := range results {
+		if result.Name != checks[i].Name || result.Index != i {
+			t.Errorf("result %d is %s (index %d), expected %s", i, result.Name, result.Index, checks[i].Name)
+		}
+		if result.Status != expected[i] {
+			t.Errorf("%s: got status %q, expected %q (err: %v)", result.Name, result.Status, expected[i], result.Error)
+		}
+	}
+}
@@ -0,0 +1,316 @@
+package health
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DatabaseStatus represents the result of a database protocol handshake
+type DatabaseStatus struct {
+	Type       string
+	Port       int
+	Status     string        // StatusRunning, StatusDegraded or StatusDown
+	Version    string        // Server version, if reported
+	ReplicaSet string        // Replication state (e.g. "rs0 primary", "master"), if any
+	RTT        time.Duration // Round trip time of the health command
+	Error      error
+}
+
+// Summary formats version, replication state and round trip time for display
+func (s DatabaseStatus) Summary() string {
+	var parts []string
+	if s.Version != "" {
+		parts = append(parts, "v"+s.Version)
+	}
+	if s.ReplicaSet != "" {
+		parts = append(parts, s.ReplicaSet)
+	}
+	if s.RTT > 0 {
+		parts = append(parts, FormatLatency(s.RTT))
+	}
+	return strings.Join(parts, " • ")
+}
+
+// CheckDatabase connects to a database on host:port and performs a real protocol
+// handshake: MongoDB hello/buildInfo, Postgres startup, or Redis PING/INFO.
+// Unknown database types fall back to a TCP port check. It gives up after timeout (default
+// 2s) or when ctx is done, whichever comes first
+func CheckDatabase(ctx context.Context, dbType, host string, port int, timeout time.Duration) DatabaseStatus {
+	if timeout == 0 {
+		timeout = 2 * time.Second
+	}
+
+	status := DatabaseStatus{Type: dbType, Port: port, Status: StatusDown}
+
+	start := time.Now()
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+
+	dialer := &net.Dialer{Deadline: deadline}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		status.Error = err
+		return status
+	}
+	defer conn.Close()
+	conn.SetDeadline(deadline)
+
+	switch strings.ToLower(dbType) {
+	case "mongodb", "mongo":
+		err = probeMongo(conn, &status)
+	case "postgres", "postgresql":
+		err = probePostgres(conn, &status)
+	case "redis":
+		err = probeRedis(conn, &status)
+	default:
+		// No protocol knowledge: an accepted connection is the best we can do
+		status.RTT = time.Since(start)
+	}
+
+	if err != nil {
+		// Something is listening but not speaking the expected protocol
+		status.Status = StatusDegraded
+		status.Error = err
+		return status
+	}
+
+	status.Status = StatusRunning
+	return status
+}
+
+// mongoCommandNotFound is the error code servers reply with for a command they don't know
+const mongoCommandNotFound = 59
+
+// probeMongo runs hello (for replica set state) and buildInfo (for version). Servers
+// that predate hello get isMaster instead
+func probeMongo(conn net.Conn, status *DatabaseStatus) error {
+	command := "hello"
+	start := time.Now()
+	hello, err := mongoCommand(conn, 1, command)
+	if err == nil && isCommandNotFound(hello) {
+		command = "isMaster"
+		hello, err = mongoCommand(conn, 3, command)
+	}
+	if err != nil {
+		return err
+	}
+	status.RTT = time.Since(start)
+
+	if ok, _ := hello["ok"].(float64); ok != 1 {
+		return fmt.Errorf("%s failed: %v", command, hello["errmsg"])
+	}
+
+	if setName, _ := hello["setName"].(string); setName != "" {
+		state := "other"
+		switch {
+		case hello["isWritablePrimary"] == true, hello["ismaster"] == true:
+			state = "primary"
+		case hello["secondary"] == true:
+			state = "secondary"
+		case hello["arbiterOnly"] == true:
+			state = "arbiter"
+		}
+		status.ReplicaSet = setName + " " + state
+	} else if hello["msg"] == "isdbgrid" {
+		status.ReplicaSet = "mongos"
+	}
+
+	info, err := mongoCommand(conn, 2, "buildInfo")
+	if err == nil {
+		status.Version, _ = info["version"].(string)
+	}
+	return nil
+}
+
+// isCommandNotFound reports whether a command reply says the server doesn't know the command
+func isCommandNotFound(reply map[string]any) bool {
+	code, _ := reply["code"].(float64)
+	return code == mongoCommandNotFound || reply["codeName"] == "CommandNotFound"
+}
+
+// postgresUnavailable are SQLSTATE codes for a server that is up but can't take sessions
+var postgresUnavailable = map[string]bool{
+	"57P03": true, // cannot_connect_now: starting up, shutting down or in recovery
+	"53300": true, // too_many_connections
+	"57P01": true, // admin_shutdown
+}
+
+// probePostgres sends a startup message and reads the server's reply. An authentication
+// request, or an error such as an unknown role or database, proves the server is accepting
+// sessions; an error saying it can't take them right now does not
+func probePostgres(conn net.Conn, status *DatabaseStatus) error {
+	params := "user\x00postgres\x00application_name\x00musing\x00\x00"
+	msg := make([]byte, 8, 8+len(params))
+	binary.BigEndian.PutUint32(msg[0:4], uint32(8+len(params)))
+	binary.BigEndian.PutUint32(msg[4:8], 196608) // Protocol 3.0
+	msg = append(msg, params...)
+
+	start := time.Now()
+	if _, err := conn.Write(msg); err != nil {
+		return err
+	}
+	defer conn.Write([]byte{'X', 0, 0, 0, 4}) // Terminate
+
+	reader := bufio.NewReader(conn)
+	for {
+		msgType, body, err := readPostgresMessage(reader)
+		if err != nil {
+			return fmt.Errorf("invalid startup response: %w", err)
+		}
+		if status.RTT == 0 {
+			status.RTT = time.Since(start)
+		}
+
+		switch msgType {
+		case 'R':
+			if len(body) < 4 {
+				return fmt.Errorf("invalid authentication message")
+			}
+			if binary.BigEndian.Uint32(body) != 0 {
+				// Password or SASL authentication required: server is up
+				return nil
+			}
+		case 'S':
+			name, value, _ := strings.Cut(string(body), "\x00")
+			if name == "server_version" {
+				status.Version = strings.TrimRight(value, "\x00")
+			}
+		case 'E':
+			// e.g. role does not exist - still a healthy server answering
+			code, message := parsePostgresError(body)
+			if postgresUnavailable[code] {
+				return fmt.Errorf("%s (SQLSTATE %s)", message, code)
+			}
+			return nil
+		case 'Z':
+			return nil
+		default:
+			// Ignore BackendKeyData and notices
+		}
+	}
+}
+
+// parsePostgresError returns the SQLSTATE code and message of an ErrorResponse, whose
+// body is a list of one-byte field types each followed by a null-terminated value
+func parsePostgresError(body []byte) (code, message string) {
+	for len(body) > 0 && body[0] != 0 {
+		field := body[0]
+		value, rest, _ := strings.Cut(string(body[1:]), "\x00")
+		switch field {
+		case 'C':
+			code = value
+		case 'M':
+			message = value
+		}
+		body = []byte(rest)
+	}
+	return code, message
+}
+
+// readPostgresMessage reads one backend message (type byte, int32 length, body)
+func readPostgresMessage(r *bufio.Reader) (byte, []byte, error) {
+	msgType, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	var length uint32
+	if err := binary.Read(r, binary.BigEndian, &length); err != nil {
+		return 0, nil, err
+	}
+	if length < 4 || length > 1<<20 {
+		return 0, nil, fmt.Errorf("bad message length %d", length)
+	}
+	body := make([]byte, length-4)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return msgType, body, nil
+}
+
+// probeRedis sends PING and, when allowed, INFO for version and role
+func probeRedis(conn net.Conn, status *DatabaseStatus) error {
+	reader := bufio.NewReader(conn)
+
+	start := time.Now()
+	reply, err := redisCommand(conn, reader, "PING")
+	if err != nil {
+		return err
+	}
+	status.RTT = time.Since(start)
+
+	if strings.HasPrefix(reply, "NOAUTH") {
+		// Server is up but needs a password for anything else
+		status.ReplicaSet = "auth required"
+		return nil
+	}
+	if reply != "PONG" {
+		return fmt.Errorf("unexpected PING reply %q", reply)
+	}
+
+	info, err := redisCommand(conn, reader, "INFO", "server")
+	if err == nil {
+		status.Version = redisInfoField(info, "redis_version")
+	}
+	info, err = redisCommand(conn, reader, "INFO", "replication")
+	if err == nil {
+		status.ReplicaSet = redisInfoField(info, "role")
+	}
+	return nil
+}
+
+// redisCommand sends a RESP command and returns a simple, error or bulk string reply
+func redisCommand(conn net.Conn, reader *bufio.Reader, args ...string) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := conn.Write([]byte(b.String())); err != nil {
+		return "", err
+	}
+
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if line == "" {
+		return "", fmt.Errorf("empty reply")
+	}
+
+	switch line[0] {
+	case '+', '-':
+		return line[1:], nil
+	case '$':
+		size, err := strconv.Atoi(line[1:])
+		if err != nil || size < 0 {
+			return "", fmt.Errorf("invalid bulk reply %q", line)
+		}
+		data := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return "", err
+		}
+		return string(data[:size]), nil
+	default:
+		return "", fmt.Errorf("unexpected reply %q", line)
+	}
+}
+
+// redisInfoField extracts a field from INFO output
+func redisInfoField(info, field string) string {
+	for _, line := range strings.Split(info, "\n") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), field+":"); ok {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,250 @@
+package health
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// serveOnce starts a listener that handles each connection with handler
+func serveOnce(t *testing.T, handler func(net.Conn)) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				handler(conn)
+			}()
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+// testBSON encodes a flat document of string, bool and float64 values
+func testBSON(fields [][2]any) []byte {
+	var buf bytes.Buffer
+	buf.Write([]byte{0, 0, 0, 0})
+	for _, field := range fields {
+		name := field[0].(string)
+		switch v := field[1].(type) {
+		case string:
+			buf.WriteByte(bsonString)
+			buf.WriteString(name + "\x00")
+			binary.Write(&buf, binary.LittleEndian, int32(len(v)+1))
+			buf.WriteString(v + "\x00")
+		case bool:
+			buf.WriteByte(bsonBool)
+			buf.WriteString(name + "\x00")
+			if v {
+				buf.WriteByte(1)
+			} else {
+				buf.WriteByte(0)
+			}
+		case float64:
+			buf.WriteByte(bsonDouble)
+			buf.WriteString(name + "\x00")
+			binary.Write(&buf, binary.LittleEndian, math.Float64bits(v))
+		}
+	}
+	buf.WriteByte(0)
+	out := buf.Bytes()
+	binary.LittleEndian.PutUint32(out, uint32(len(out)))
+	return out
+}
+
+// TestCheckDatabaseMongo tests the MongoDB hello/buildInfo handshake against a fake server,
+// and the isMaster fallback for servers that predate hello
+func TestCheckDatabaseMongo(t *testing.T) {
+	tests := []struct {
+		name  string
+		reply func(request map[string]any) []byte
+	}{
+		{
+			name: "hello",
+			reply: func(request map[string]any) []byte {
+				if _, ok := request["hello"]; ok {
+					return testBSON([][2]any{{"isWritablePrimary", true}, {"setName", "rs0"}, {"ok", 1.0}})
+				}
+				return testBSON([][2]any{{"version", "7.0.4"}, {"ok", 1.0}})
+			},
+		},
+		{
+			name: "before hello",
+			reply: func(request map[string]any) []byte {
+				switch {
+				case request["hello"] != nil:
+					return testBSON([][2]any{{"ok", 0.0}, {"errmsg", "no such command: 'hello'"}, {"code", 59.0}, {"codeName", "CommandNotFound"}})
+				case request["isMaster"] != nil:
+					return testBSON([][2]any{{"ismaster", true}, {"setName", "rs0"}, {"ok", 1.0}})
+				}
+				return testBSON([][2]any{{"version", "7.0.4"}, {"ok", 1.0}})
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			port := serveOnce(t, func(conn net.Conn) {
+				for {
+					header := make([]byte, 16)
+					if _, err := io.ReadFull(conn, header); err != nil {
+						return
+					}
+					body := make([]byte, binary.LittleEndian.Uint32(header)-16)
+					if _, err := io.ReadFull(conn, body); err != nil {
+						return
+					}
+					request, _, err := decodeBSON(body[5:])
+					if err != nil {
+						t.Errorf("server failed to decode request: %v", err)
+						return
+					}
+
+					reply := tt.reply(request)
+					msg := make([]byte, 16, 21+len(reply))
+					binary.LittleEndian.PutUint32(msg[12:], opMsg)
+					msg = append(msg, 0, 0, 0, 0, 0)
+					msg = append(msg, reply...)
+					binary.LittleEndian.PutUint32(msg, uint32(len(msg)))
+					conn.Write(msg)
+				}
+			})
+
+			status := CheckDatabase(context.Background(), "MongoDB", "127.0.0.1", port, time.Second)
+			if status.Status != StatusRunning {
+				t.Fatalf("status = %q (err: %v), want running", status.Status, status.Error)
+			}
+			if status.Version != "7.0.4" || status.ReplicaSet != "rs0 primary" {
+				t.Errorf("version = %q, replica set = %q", status.Version, status.ReplicaSet)
+			}
+		})
+	}
+}
+
+// TestCheckDatabaseRedis tests the Redis PING/INFO handshake against a fake server
+func TestCheckDatabaseRedis(t *testing.T) {
+	port := serveOnce(t, func(conn net.Conn) {
+		reader := bufio.NewReader(conn)
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			var args []string
+			count, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+			for range count {
+				reader.ReadString('\n')
+				arg, _ := reader.ReadString('\n')
+				args = append(args, strings.TrimSpace(arg))
+			}
+
+			switch {
+			case args[0] == "PING":
+				conn.Write([]byte("+PONG\r\n"))
+			case args[1] == "server":
+				info := "# Server\r\nredis_version:7.2.1\r\n"
+				conn.Write([]byte("$" + strconv.Itoa(len(info)) + "\r\n" + info + "\r\n"))
+			default:
+				info := "# Replication\r\nrole:master\r\n"
+				conn.Write([]byte("$" + strconv.Itoa(len(info)) + "\r\n" + info + "\r\n"))
+			}
+		}
+	})
+
+	status := CheckDatabase(context.Background(), "redis", "127.0.0.1", port, time.Second)
+	if status.Status != StatusRunning || status.Version != "7.2.1" || status.ReplicaSet != "master" {
+		t.Errorf("CheckDatabase() = %+v", status)
+	}
+}
+
+// TestCheckDatabasePostgres tests the Postgres startup handshake against a fake server
+func TestCheckDatabasePostgres(t *testing.T) {
+	tests := []struct {
+		name     string
+		reply    []byte
+		expected string
+	}{
+		{
+			name:     "password required",
+			reply:    []byte{'R', 0, 0, 0, 12, 0, 0, 0, 5, 1, 2, 3, 4}, // AuthenticationMD5Password
+			expected: StatusRunning,
+		},
+		{
+			name:     "unknown role",
+			reply:    postgresError("28000", `role "postgres" does not exist`),
+			expected: StatusRunning,
+		},
+		{
+			name:     "unknown database",
+			reply:    postgresError("3D000", `database "postgres" does not exist`),
+			expected: StatusRunning,
+		},
+		{
+			name:     "starting up",
+			reply:    postgresError("57P03", "the database system is starting up"),
+			expected: StatusDegraded,
+		},
+		{
+			name:     "too many connections",
+			reply:    postgresError("53300", "sorry, too many clients already"),
+			expected: StatusDegraded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			port := serveOnce(t, func(conn net.Conn) {
+				header := make([]byte, 8)
+				if _, err := io.ReadFull(conn, header); err != nil {
+					return
+				}
+				io.ReadFull(conn, make([]byte, binary.BigEndian.Uint32(header)-8))
+				conn.Write(tt.reply)
+				io.Copy(io.Discard, conn)
+			})
+
+			status := CheckDatabase(context.Background(), "postgres", "127.0.0.1", port, time.Second)
+			if status.Status != tt.expected {
+				t.Errorf("status = %q (err: %v), want %q", status.Status, status.Error, tt.expected)
+			}
+		})
+	}
+}
+
+// postgresError builds an ErrorResponse with a severity, SQLSTATE code and message
+func postgresError(code, message string) []byte {
+	fields := "SFATAL\x00C" + code + "\x00M" + message + "\x00\x00"
+	msg := []byte{'E', 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(msg[1:], uint32(4+len(fields)))
+	return append(msg, fields...)
+}
+
+// TestCheckDatabaseWrongProtocol tests that a listener speaking another protocol is degraded
+func TestCheckDatabaseWrongProtocol(t *testing.T) {
+	port := serveOnce(t, func(conn net.Conn) {
+		conn.Write([]byte("HTTP/1.1 400 Bad Request\r\n\r\n"))
+	})
+
+	status := CheckDatabase(context.Background(), "MongoDB", "127.0.0.1", port, time.Second)
+	if status.Status != StatusDegraded {
+		t.Errorf("status = %q, want degraded", status.Status)
+	}
+}
@@ -0,0 +1,209 @@
+package health
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// grpc.health.v1 serving states (HealthCheckResponse.ServingStatus)
+const (
+	ServingUnknown        = "UNKNOWN"
+	ServingServing        = "SERVING"
+	ServingNotServing     = "NOT_SERVING"
+	ServingServiceUnknown = "SERVICE_UNKNOWN"
+)
+
+var servingStatuses = []string{ServingUnknown, ServingServing, ServingNotServing, ServingServiceUnknown}
+
+// gRPC status codes with a specific meaning for health checks
+const (
+	grpcNotFound      = 5
+	grpcUnimplemented = 12
+)
+
+// GRPCCheck configures a call to the standard grpc.health.v1.Health/Check method
+type GRPCCheck struct {
+	Address       string        // host:port
+	Service       string        // Service name to check (default: the server as a whole)
+	TLS           bool          // Connect with TLS instead of plaintext HTTP/2
+	TLSSkipVerify bool          // Don't verify the server certificate (e.g. self-signed dev certs)
+	Timeout       time.Duration // Call timeout (default: 3s)
+}
+
+// GRPCStatus represents the result of a gRPC health check
+type GRPCStatus struct {
+	Status  string // StatusRunning, StatusDegraded or StatusDown
+	Serving string // Serving status reported by the server, e.g. SERVING
+	Latency time.Duration
+	Error   error
+}
+
+// CheckGRPC calls grpc.health.v1.Health/Check. SERVING is running; NOT_SERVING, UNKNOWN,
+// an unknown service or a server without the health service is degraded; a server
+// that can't be reached is down
+func CheckGRPC(ctx context.Context, check GRPCCheck) GRPCStatus {
+	timeout := check.Timeout
+	if timeout == 0 {
+		timeout = 3 * time.Second
+	}
+
+	// gRPC is HTTP/2 with a length-prefixed protobuf body and status in the trailers
+	protocols := new(http.Protocols)
+	transport := &http.Transport{Protocols: protocols}
+	scheme := "http"
+	if check.TLS {
+		scheme = "https"
+		protocols.SetHTTP2(true)
+		transport.TLSClientConfig = &tls.Config{InsecureSkipVerify: check.TLSSkipVerify}
+	} else {
+		protocols.SetUnencryptedHTTP2(true)
+	}
+	defer transport.CloseIdleConnections()
+
+	client := &http.Client{Transport: transport, Timeout: timeout}
+	url := fmt.Sprintf("%s://%s/grpc.health.v1.Health/Check", scheme, check.Address)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(grpcFrame(healthCheckRequest(check.Service))))
+	if err != nil {
+		return GRPCStatus{Status: StatusDown, Error: err}
+	}
+	req.Header.Set("Content-Type", "application/grpc")
+	req.Header.Set("TE", "trailers")
+
+	result := GRPCStatus{Status: StatusDown}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		result.Error = err
+		if acceptsConnections(ctx, req.URL) {
+			result.Status = StatusDegraded // Listening, but not speaking gRPC
+		}
+		return result
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	result.Latency = time.Since(start)
+	result.Status = StatusDegraded
+	if err != nil {
+		result.Error = fmt.Errorf("failed to read response: %w", err)
+		return result
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		result.Error = fmt.Errorf("unexpected HTTP status %d", resp.StatusCode)
+		return result
+	}
+
+	// Trailers-only responses carry the status in the headers
+	code := resp.Trailer.Get("Grpc-Status")
+	if code == "" {
+		code = resp.Header.Get("Grpc-Status")
+	}
+	switch code {
+	case "0":
+	case strconv.Itoa(grpcNotFound):
+		result.Serving = ServingServiceUnknown
+		result.Error = fmt.Errorf("service %q unknown to the health server", check.Service)
+		return result
+	case strconv.Itoa(grpcUnimplemented):
+		result.Error = fmt.Errorf("server does not implement grpc.health.v1.Health")
+		return result
+	default:
+		message := resp.Trailer.Get("Grpc-Message")
+		if message == "" {
+			message = resp.Header.Get("Grpc-Message")
+		}
+		result.Error = fmt.Errorf("grpc status %s: %s", code, message)
+		return result
+	}
+
+	serving, err := parseHealthCheckResponse(body)
+	if err != nil {
+		result.Error = err
+		return result
+	}
+	result.Serving = serving
+	if serving != ServingServing {
+		result.Error = fmt.Errorf("server reports %s", serving)
+		return result
+	}
+
+	result.Status = StatusRunning
+	return result
+}
+
+// healthCheckRequest encodes HealthCheckRequest{service = 1}
+func healthCheckRequest(service string) []byte {
+	if service == "" {
+		return nil
+	}
+	msg := []byte{0x0a} // Field 1, wire type 2 (length-delimited)
+	msg = binary.AppendUvarint(msg, uint64(len(service)))
+	return append(msg, service...)
+}
+
+// grpcFrame adds the gRPC message prefix: an uncompressed flag and big-endian length
+func grpcFrame(msg []byte) []byte {
+	frame := make([]byte, 5, 5+len(msg))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(msg)))
+	return append(frame, msg...)
+}
+
+// parseHealthCheckResponse decodes the serving status from a framed HealthCheckResponse
+func parseHealthCheckResponse(body []byte) (string, error) {
+	if len(body) < 5 {
+		return "", fmt.Errorf("empty gRPC response")
+	}
+	if body[0] != 0 {
+		return "", fmt.Errorf("compressed gRPC responses are not supported")
+	}
+	size := binary.BigEndian.Uint32(body[1:5])
+	if int(size) > len(body)-5 {
+		return "", fmt.Errorf("truncated gRPC response")
+	}
+	msg := body[5 : 5+size]
+
+	// proto3 omits the default value, so an empty message means UNKNOWN
+	status := uint64(0)
+	for len(msg) > 0 {
+		key, n := binary.Uvarint(msg)
+		if n <= 0 {
+			return "", fmt.Errorf("invalid HealthCheckResponse")
+		}
+		msg = msg[n:]
+
+		switch key & 7 {
+		case 0: // varint
+			value, n := binary.Uvarint(msg)
+			if n <= 0 {
+				return "", fmt.Errorf("invalid HealthCheckResponse")
+			}
+			msg = msg[n:]
+			if key>>3 == 1 {
+				status = value
+			}
+		case 2: // length-delimited, skipped
+			length, n := binary.Uvarint(msg)
+			if n <= 0 || uint64(len(msg)-n) < length {
+				return "", fmt.Errorf("invalid HealthCheckResponse")
+			}
+			msg = msg[uint64(n)+length:]
+		default:
+			return "", fmt.Errorf("unexpected wire type %d in HealthCheckResponse", key&7)
+		}
+	}
+
+	if status >= uint64(len(servingStatuses)) {
+		return ServingUnknown, nil
+	}
+	return servingStatuses[status], nil
+}
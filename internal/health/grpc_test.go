@@ -0,0 +1,68 @@
+package health
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestCheckGRPC tests serving status mapping against a plaintext HTTP/2 stand-in health server
+func TestCheckGRPC(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/grpc.health.v1.Health/Check" || r.ProtoMajor != 2 {
+			w.Header().Set("Grpc-Status", "12")
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		service := ""
+		if len(body) > 7 {
+			service = string(body[7:]) // Frame prefix, field tag and length
+		}
+
+		w.Header().Set("Content-Type", "application/grpc")
+		w.Header().Set("Trailer", "Grpc-Status")
+		switch service {
+		case "missing":
+			w.Header().Set("Grpc-Status", "5") // Trailers-only NOT_FOUND
+			return
+		case "stopped":
+			w.Write(grpcFrame([]byte{0x08, 2})) // NOT_SERVING
+		case "":
+			w.Write(grpcFrame([]byte{0x08, 1})) // SERVING
+		default:
+			w.Write(grpcFrame(nil)) // UNKNOWN (default value omitted)
+		}
+		w.Header().Set("Grpc-Status", "0")
+	}))
+	server.Config.Protocols = new(http.Protocols)
+	server.Config.Protocols.SetHTTP1(true)
+	server.Config.Protocols.SetUnencryptedHTTP2(true)
+	server.Start()
+	defer server.Close()
+
+	address := strings.TrimPrefix(server.URL, "http://")
+
+	tests := []struct {
+		service  string
+		expected string
+		serving  string
+	}{
+		{"", StatusRunning, ServingServing},
+		{"stopped", StatusDegraded, ServingNotServing},
+		{"starting", StatusDegraded, ServingUnknown},
+		{"missing", StatusDegraded, ServingServiceUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.service, func(t *testing.T) {
+			status := CheckGRPC(context.Background(), GRPCCheck{Address: address, Service: tt.service})
+			if status.Status != tt.expected || status.Serving != tt.serving {
+				t.Errorf("got %s/%s, expected %s/%s (err: %v)", status.Status, status.Serving, tt.expected, tt.serving, status.Error)
+			}
+		})
+	}
+}
@@ -1,9 +1,15 @@
 package health
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
+	"net/url"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -14,20 +20,41 @@ type PortStatus struct {
 	Latency time.Duration
 }
 
+// Service status values shared by all checks
+const (
+	StatusRunning  = "running"  // Healthy
+	StatusDegraded = "degraded" // Reachable but failing its health check
+	StatusDown     = "down"     // Not reachable
+)
+
+// HTTPCheck configures an HTTP health check
+type HTTPCheck struct {
+	URL       string            // Full URL to request
+	Status    []int             // Accepted status codes (default: any 2xx)
+	Body      string            // Substring the response body must contain
+	JSONField string            // Dot-separated JSON field to inspect (e.g. "checks.db.status")
+	JSONValue string            // Expected value of JSONField
+	Timeout   time.Duration     // Request timeout (default: 3s)
+	Headers   map[string]string // Extra request headers
+}
+
 // HTTPStatus represents the status of an HTTP health check
 type HTTPStatus struct {
-	URL       string
-	Available bool
-	Latency   time.Duration
-	Error     error
+	URL        string
+	Status     string // StatusRunning, StatusDegraded or StatusDown
+	Available  bool
+	StatusCode int
+	Latency    time.Duration
+	Error      error
 }
 
-// CheckPort checks if a port is open on localhost
-func CheckPort(port int) PortStatus {
+// CheckPort checks if a port is open on localhost, giving up after 2s or when ctx is done
+func CheckPort(ctx context.Context, port int) PortStatus {
 	start := time.Now()
 	addr := fmt.Sprintf("localhost:%d", port)
 
-	conn, err := net.DialTimeout("tcp", addr, 2*time.Second)
+	dialer := &net.Dialer{Timeout: 2 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
 	latency := time.Since(start)
 
 	if err != nil {
@@ -46,35 +73,130 @@ func CheckPort(port int) PortStatus {
 	}
 }
 
-// CheckHTTP performs an HTTP health check
-func CheckHTTP(url string) HTTPStatus {
-	start := time.Now()
+// CheckHTTP performs an HTTP health check. A service that cannot be reached is down;
+// one that responds but fails the status, body or JSON expectations is degraded
+func CheckHTTP(ctx context.Context, check HTTPCheck) HTTPStatus {
+	return checkHTTP(ctx, check, nil)
+}
+
+// checkHTTP performs an HTTP health check using transport (nil for the default)
+func checkHTTP(ctx context.Context, check HTTPCheck, transport http.RoundTripper) HTTPStatus {
+	timeout := check.Timeout
+	if timeout == 0 {
+		timeout = 3 * time.Second
+	}
 
 	client := &http.Client{
-		Timeout: 3 * time.Second,
+		Timeout:   timeout,
+		Transport: transport,
 	}
 
-	resp, err := client.Get(url)
-	latency := time.Since(start)
+	result := HTTPStatus{URL: check.URL, Status: StatusDown}
 
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, check.URL, nil)
 	if err != nil {
-		return HTTPStatus{
-			URL:       url,
-			Available: false,
-			Latency:   0,
-			Error:     err,
+		result.Error = err
+		return result
+	}
+	for name, value := range check.Headers {
+		req.Header.Set(name, value)
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		result.Error = err
+		if acceptsConnections(ctx, req.URL) {
+			// Listening, but not answering HTTP properly
+			result.Status = StatusDegraded
 		}
+		return result
 	}
 	defer resp.Body.Close()
 
-	available := resp.StatusCode >= 200 && resp.StatusCode < 300
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	result.Latency = time.Since(start)
+	result.StatusCode = resp.StatusCode
+	result.Status = StatusDegraded
+	if err != nil {
+		result.Error = fmt.Errorf("failed to read response: %w", err)
+		return result
+	}
+
+	if err := check.verify(resp.StatusCode, body); err != nil {
+		result.Error = err
+		return result
+	}
+
+	result.Status = StatusRunning
+	result.Available = true
+	return result
+}
+
+// verify checks a response against the configured expectations
+func (c HTTPCheck) verify(statusCode int, body []byte) error {
+	if len(c.Status) > 0 {
+		if !slices.Contains(c.Status, statusCode) {
+			return fmt.Errorf("unexpected status %d", statusCode)
+		}
+	} else if statusCode < 200 || statusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", statusCode)
+	}
 
-	return HTTPStatus{
-		URL:       url,
-		Available: available,
-		Latency:   latency,
-		Error:     nil,
+	if c.Body != "" && !strings.Contains(string(body), c.Body) {
+		return fmt.Errorf("response body does not contain %q", c.Body)
 	}
+
+	if c.JSONField != "" {
+		var doc any
+		if err := json.Unmarshal(body, &doc); err != nil {
+			return fmt.Errorf("response is not valid JSON")
+		}
+		value, ok := lookupJSONField(doc, c.JSONField)
+		if !ok {
+			return fmt.Errorf("JSON field %s missing", c.JSONField)
+		}
+		if c.JSONValue != "" && fmt.Sprint(value) != c.JSONValue {
+			return fmt.Errorf("JSON field %s is %v, want %s", c.JSONField, value, c.JSONValue)
+		}
+	}
+
+	return nil
+}
+
+// lookupJSONField walks a dot-separated path through decoded JSON objects
+func lookupJSONField(doc any, path string) (any, bool) {
+	current := doc
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := current.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		current, ok = obj[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// acceptsConnections reports whether the URL's host accepts TCP connections
+func acceptsConnections(ctx context.Context, u *url.URL) bool {
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+
+	dialer := &net.Dialer{Timeout: time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(u.Hostname(), port))
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
 }
 
 // FormatLatency formats a duration for display (in milliseconds only)
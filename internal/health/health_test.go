@@ -0,0 +1,85 @@
+package health
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestCheckHTTP tests HTTP health check expectations and status classification
+func TestCheckHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			w.Write([]byte(`{"status":"ok","checks":{"db":"up"}}`))
+		case "/error":
+			w.WriteHeader(http.StatusInternalServerError)
+		case "/auth":
+			if r.Header.Get("Authorization") != "Bearer token" {
+				w.WriteHeader(http.StatusUnauthorized)
+			}
+		}
+	}))
+	defer server.Close()
+
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closedURL := closed.URL
+	closed.Close()
+
+	tests := []struct {
+		name     string
+		check    HTTPCheck
+		expected string
+	}{
+		{
+			name:     "healthy",
+			check:    HTTPCheck{URL: server.URL + "/health"},
+			expected: StatusRunning,
+		},
+		{
+			name:     "server error is degraded",
+			check:    HTTPCheck{URL: server.URL + "/error"},
+			expected: StatusDegraded,
+		},
+		{
+			name:     "accepted status code",
+			check:    HTTPCheck{URL: server.URL + "/error", Status: []int{500}},
+			expected: StatusRunning,
+		},
+		{
+			name:     "body substring mismatch",
+			check:    HTTPCheck{URL: server.URL + "/health", Body: "healthy"},
+			expected: StatusDegraded,
+		},
+		{
+			name:     "nested JSON field match",
+			check:    HTTPCheck{URL: server.URL + "/health", JSONField: "checks.db", JSONValue: "up"},
+			expected: StatusRunning,
+		},
+		{
+			name:     "JSON field mismatch",
+			check:    HTTPCheck{URL: server.URL + "/health", JSONField: "status", JSONValue: "degraded"},
+			expected: StatusDegraded,
+		},
+		{
+			name:     "headers",
+			check:    HTTPCheck{URL: server.URL + "/auth", Headers: map[string]string{"Authorization": "Bearer token"}},
+			expected: StatusRunning,
+		},
+		{
+			name:     "not listening is down",
+			check:    HTTPCheck{URL: closedURL + "/health"},
+			expected: StatusDown,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CheckHTTP(context.Background(), tt.check)
+			if result.Status != tt.expected {
+				t.Errorf("CheckHTTP() status = %q (err: %v), want %q", result.Status, result.Error, tt.expected)
+			}
+		})
+	}
+}
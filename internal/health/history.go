@@ -0,0 +1,249 @@
+package health
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// Flapping detection: a service is flapping when its status changed at least
+// FlapTransitions times within its last FlapWindow samples
+const (
+	FlapWindow      = 10
+	FlapTransitions = 4
+)
+
+// DefaultHistorySize is the number of samples kept per service
+const DefaultHistorySize = 500
+
+// Sample is one recorded check result
+type Sample struct {
+	Time    time.Time     `json:"time"`
+	Service string        `json:"service"`
+	Status  string        `json:"status"`
+	Latency time.Duration `json:"latency"`
+}
+
+// Stats summarises a service's recorded history
+type Stats struct {
+	Service     string
+	Samples     int
+	Status      string        // Latest status
+	Uptime      float64       // Percentage of samples that weren't down
+	P50         time.Duration // Median latency of successful checks
+	P95         time.Duration // 95th percentile latency of successful checks
+	Since       time.Time     // When the current status began (first sample if it never changed)
+	Transitions int           // Status changes across the kept samples
+	Flapping    bool
+}
+
+// History keeps a rolling window of samples per service
+type History struct {
+	size     int
+	order    []string // Services in the order first recorded
+	samples  map[string][]Sample
+	changes  map[string]time.Time // Last status change per service
+	persist  bool                 // Loaded from a file, so samples are kept for Append
+	unsynced []Sample             // Samples recorded since the last Append
+}
+
+// NewHistory creates an empty history keeping up to size samples per service
+func NewHistory(size int) *History {
+	if size <= 0 {
+		size = DefaultHistorySize
+	}
+	return &History{
+		size:    size,
+		samples: map[string][]Sample{},
+		changes: map[string]time.Time{},
+	}
+}
+
+// Record adds a sample and reports whether the service's status changed
+func (h *History) Record(sample Sample) bool {
+	samples, seen := h.samples[sample.Service]
+	if !seen {
+		h.order = append(h.order, sample.Service)
+	}
+
+	changed := len(samples) > 0 && samples[len(samples)-1].Status != sample.Status
+	if changed {
+		h.changes[sample.Service] = sample.Time
+	}
+
+	samples = append(samples, sample)
+	if len(samples) > h.size {
+		samples = samples[len(samples)-h.size:]
+	}
+	h.samples[sample.Service] = samples
+	if h.persist {
+		h.unsynced = append(h.unsynced, sample)
+	}
+
+	return changed
+}
+
+// Services returns the recorded services in the order they were first seen
+func (h *History) Services() []string {
+	return h.order
+}
+
+// Stats summarises the history of a service
+func (h *History) Stats(service string) Stats {
+	samples := h.samples[service]
+	stats := Stats{Service: service, Samples: len(samples)}
+	if len(samples) == 0 {
+		return stats
+	}
+	stats.Status = samples[len(samples)-1].Status
+
+	stats.Since = samples[0].Time
+	if changed, ok := h.changes[service]; ok {
+		stats.Since = changed
+	}
+
+	up := 0
+	var latencies []time.Duration
+	for i, sample := range samples {
+		if sample.Status != StatusDown {
+			up++
+			if sample.Latency > 0 {
+				latencies = append(latencies, sample.Latency)
+			}
+		}
+		if i > 0 && sample.Status != samples[i-1].Status {
+			stats.Transitions++
+		}
+	}
+	stats.Uptime = 100 * float64(up) / float64(len(samples))
+
+	if len(latencies) > 0 {
+		slices.Sort(latencies)
+		stats.P50 = percentile(latencies, 50)
+		stats.P95 = percentile(latencies, 95)
+	}
+
+	recent := samples[max(0, len(samples)-FlapWindow):]
+	recentTransitions := 0
+	for i := 1; i < len(recent); i++ {
+		if recent[i].Status != recent[i-1].Status {
+			recentTransitions++
+		}
+	}
+	stats.Flapping = recentTransitions >= FlapTransitions
+
+	return stats
+}
+
+// percentile returns the p-th percentile of sorted durations (nearest rank)
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
+
+// LoadHistory reads a history file written by Append. A missing file gives an empty history.
+// Only loaded histories keep samples for Append; in-memory ones don't buffer them
+func LoadHistory(path string, size int) (*History, error) {
+	h := NewHistory(size)
+	h.persist = true
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var sample Sample
+		if err := json.Unmarshal(scanner.Bytes(), &sample); err != nil {
+			continue // Skip lines torn by an interrupted write
+		}
+		h.Record(sample)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	h.unsynced = nil // Already in the file
+	return h, nil
+}
+
+// Append writes samples recorded since the last call to the history file, one JSON
+// object per line. The file is compacted to the kept window once it grows well past it
+func (h *History) Append(path string) error {
+	if len(h.unsynced) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if err := writeSamples(file, h.unsynced); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	h.unsynced = nil
+
+	if info, err := os.Stat(path); err == nil && info.Size() > h.compactSize() {
+		return h.compact(path)
+	}
+	return nil
+}
+
+// compactSize is the file size above which Append rewrites the file (roughly
+// twice the bytes needed to hold the kept window)
+func (h *History) compactSize() int64 {
+	return int64(2 * 120 * h.size * max(len(h.order), 1))
+}
+
+// compact rewrites the history file with only the kept samples
+func (h *History) compact(path string) error {
+	var all []Sample
+	for _, service := range h.order {
+		all = append(all, h.samples[service]...)
+	}
+	slices.SortStableFunc(all, func(a, b Sample) int {
+		return a.Time.Compare(b.Time)
+	})
+
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if err := writeSamples(file, all); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// writeSamples writes samples as JSON lines
+func writeSamples(file *os.File, samples []Sample) error {
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, sample := range samples {
+		if err := encoder.Encode(sample); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
@@ -0,0 +1,73 @@
+package health
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestHistoryStats tests uptime, latency percentiles, transitions and flapping
+func TestHistoryStats(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	statuses := []string{StatusRunning, StatusRunning, StatusDown, StatusRunning, StatusDown, StatusRunning, StatusDegraded, StatusRunning}
+
+	path := filepath.Join(t.TempDir(), "health.db")
+	h, err := LoadHistory(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var changes int
+	for i, status := range statuses {
+		if h.Record(Sample{
+			Time:    start.Add(time.Duration(i) * time.Second),
+			Service: "api",
+			Status:  status,
+			Latency: time.Duration(i+1) * time.Millisecond,
+		}) {
+			changes++
+		}
+	}
+
+	stats := h.Stats("api")
+	if changes != 6 || stats.Transitions != 6 {
+		t.Errorf("got %d changes and %d transitions, expected 6", changes, stats.Transitions)
+	}
+	if stats.Uptime != 75 {
+		t.Errorf("Uptime = %v, expected 75", stats.Uptime)
+	}
+	if stats.P50 != 4*time.Millisecond || stats.P95 != 8*time.Millisecond {
+		t.Errorf("P50 = %v, P95 = %v, expected 4ms and 8ms", stats.P50, stats.P95)
+	}
+	if !stats.Flapping {
+		t.Error("expected service to be flapping")
+	}
+	if !stats.Since.Equal(start.Add(7 * time.Second)) {
+		t.Errorf("Since = %v, expected the last change", stats.Since)
+	}
+
+	// Round trip through the history file
+	if err := h.Append(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadHistory(path, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := loaded.Stats("api"); got != stats {
+		t.Errorf("loaded stats = %+v, expected %+v", got, stats)
+	}
+}
+
+// TestHistoryInMemory tests that a history that isn't persisted doesn't buffer samples for Append
+func TestHistoryInMemory(t *testing.T) {
+	h := NewHistory(10)
+	for i := range 1000 {
+		h.Record(Sample{Time: time.Unix(int64(i), 0), Service: "api", Status: StatusRunning})
+	}
+	if len(h.unsynced) != 0 {
+		t.Errorf("buffered %d unsynced samples, expected none", len(h.unsynced))
+	}
+	if stats := h.Stats("api"); stats.Samples != 10 {
+		t.Errorf("kept %d samples, expected 10", stats.Samples)
+	}
+}
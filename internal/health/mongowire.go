@@ -0,0 +1,209 @@
+package health
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"net"
+)
+
+// MongoDB wire protocol constants
+const (
+	opMsg          = 2013
+	maxMongoReply  = 16 << 20
+	bsonDouble     = 0x01
+	bsonString     = 0x02
+	bsonDocument   = 0x03
+	bsonArray      = 0x04
+	bsonBinary     = 0x05
+	bsonUndefined  = 0x06
+	bsonObjectID   = 0x07
+	bsonBool       = 0x08
+	bsonDateTime   = 0x09
+	bsonNull       = 0x0A
+	bsonRegex      = 0x0B
+	bsonInt32      = 0x10
+	bsonTimestamp  = 0x11
+	bsonInt64      = 0x12
+	bsonDecimal128 = 0x13
+	bsonMinKey     = 0xFF
+	bsonMaxKey     = 0x7F
+)
+
+// mongoCommand runs {<command>: 1, $db: "admin"} over OP_MSG and returns the reply document.
+// Numbers are decoded as float64, nested documents and arrays as map[string]any
+func mongoCommand(conn net.Conn, requestID int32, command string) (map[string]any, error) {
+	doc := encodeBSON(command, "admin")
+
+	msg := make([]byte, 16, 16+5+len(doc))
+	binary.LittleEndian.PutUint32(msg[4:8], uint32(requestID))
+	binary.LittleEndian.PutUint32(msg[12:16], opMsg)
+	msg = append(msg, 0, 0, 0, 0) // flagBits
+	msg = append(msg, 0)          // Section kind 0: body
+	msg = append(msg, doc...)
+	binary.LittleEndian.PutUint32(msg[0:4], uint32(len(msg)))
+
+	if _, err := conn.Write(msg); err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 16)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return nil, fmt.Errorf("no reply to %s: %w", command, err)
+	}
+	length := binary.LittleEndian.Uint32(header[0:4])
+	if length < 16+5 || length > maxMongoReply {
+		return nil, fmt.Errorf("invalid reply length %d", length)
+	}
+	if opCode := binary.LittleEndian.Uint32(header[12:16]); opCode != opMsg {
+		return nil, fmt.Errorf("unexpected reply opcode %d", opCode)
+	}
+
+	body := make([]byte, length-16)
+	if _, err := io.ReadFull(conn, body); err != nil {
+		return nil, err
+	}
+	if body[4] != 0 {
+		return nil, fmt.Errorf("unexpected reply section kind %d", body[4])
+	}
+
+	reply, _, err := decodeBSON(body[5:])
+	return reply, err
+}
+
+// encodeBSON builds the document {<command>: int32(1), $db: <db>}
+func encodeBSON(command, db string) []byte {
+	var buf bytes.Buffer
+	buf.Write([]byte{0, 0, 0, 0}) // Length placeholder
+
+	buf.WriteByte(bsonInt32)
+	buf.WriteString(command)
+	buf.WriteByte(0)
+	binary.Write(&buf, binary.LittleEndian, int32(1))
+
+	buf.WriteByte(bsonString)
+	buf.WriteString("$db")
+	buf.WriteByte(0)
+	binary.Write(&buf, binary.LittleEndian, int32(len(db)+1))
+	buf.WriteString(db)
+	buf.WriteByte(0)
+
+	buf.WriteByte(0) // Document terminator
+
+	out := buf.Bytes()
+	binary.LittleEndian.PutUint32(out[0:4], uint32(len(out)))
+	return out
+}
+
+// decodeBSON decodes a BSON document, returning it and the number of bytes consumed
+func decodeBSON(data []byte) (map[string]any, int, error) {
+	if len(data) < 5 {
+		return nil, 0, fmt.Errorf("bson: document too short")
+	}
+	size := int(binary.LittleEndian.Uint32(data[0:4]))
+	if size < 5 || size > len(data) {
+		return nil, 0, fmt.Errorf("bson: invalid document size %d", size)
+	}
+
+	doc := map[string]any{}
+	pos := 4
+	for pos < size-1 {
+		elemType := data[pos]
+		pos++
+
+		nameEnd := bytes.IndexByte(data[pos:size], 0)
+		if nameEnd < 0 {
+			return nil, 0, fmt.Errorf("bson: unterminated element name")
+		}
+		name := string(data[pos : pos+nameEnd])
+		pos += nameEnd + 1
+
+		value, n, err := decodeBSONValue(elemType, data[pos:size])
+		if err != nil {
+			return nil, 0, fmt.Errorf("bson: field %s: %w", name, err)
+		}
+		doc[name] = value
+		pos += n
+	}
+
+	return doc, size, nil
+}
+
+// decodeBSONValue decodes a single element value of the given type
+func decodeBSONValue(elemType byte, data []byte) (any, int, error) {
+	need := func(n int) error {
+		if len(data) < n {
+			return fmt.Errorf("truncated value")
+		}
+		return nil
+	}
+
+	switch elemType {
+	case bsonDouble:
+		if err := need(8); err != nil {
+			return nil, 0, err
+		}
+		return math.Float64frombits(binary.LittleEndian.Uint64(data)), 8, nil
+	case bsonString:
+		if err := need(4); err != nil {
+			return nil, 0, err
+		}
+		n := int(binary.LittleEndian.Uint32(data))
+		if n < 1 || len(data) < 4+n {
+			return nil, 0, fmt.Errorf("invalid string length")
+		}
+		return string(data[4 : 4+n-1]), 4 + n, nil
+	case bsonDocument, bsonArray:
+		return decodeBSON(data)
+	case bsonBinary:
+		if err := need(5); err != nil {
+			return nil, 0, err
+		}
+		n := int(binary.LittleEndian.Uint32(data))
+		if err := need(5 + n); err != nil {
+			return nil, 0, err
+		}
+		return data[5 : 5+n], 5 + n, nil
+	case bsonObjectID:
+		if err := need(12); err != nil {
+			return nil, 0, err
+		}
+		return fmt.Sprintf("%x", data[:12]), 12, nil
+	case bsonBool:
+		if err := need(1); err != nil {
+			return nil, 0, err
+		}
+		return data[0] == 1, 1, nil
+	case bsonDateTime, bsonTimestamp, bsonInt64:
+		if err := need(8); err != nil {
+			return nil, 0, err
+		}
+		return float64(int64(binary.LittleEndian.Uint64(data))), 8, nil
+	case bsonNull, bsonUndefined, bsonMinKey, bsonMaxKey:
+		return nil, 0, nil
+	case bsonRegex:
+		pattern := bytes.IndexByte(data, 0)
+		if pattern < 0 {
+			return nil, 0, fmt.Errorf("unterminated regex")
+		}
+		options := bytes.IndexByte(data[pattern+1:], 0)
+		if options < 0 {
+			return nil, 0, fmt.Errorf("unterminated regex options")
+		}
+		return string(data[:pattern]), pattern + options + 2, nil
+	case bsonInt32:
+		if err := need(4); err != nil {
+			return nil, 0, err
+		}
+		return float64(int32(binary.LittleEndian.Uint32(data))), 4, nil
+	case bsonDecimal128:
+		if err := need(16); err != nil {
+			return nil, 0, err
+		}
+		return nil, 16, nil
+	default:
+		return nil, 0, fmt.Errorf("unsupported type 0x%02x", elemType)
+	}
+}
@@ -0,0 +1,82 @@
+package health
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// TestCheckRemote tests TLS verification, expiry warnings and HTTP status for remote endpoints
+func TestCheckRemote(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	trusted := x509.NewCertPool()
+	trusted.AddCert(server.Certificate())
+
+	// The test certificate is valid for 127.0.0.1 and example.com, but not localhost
+	port := strings.TrimPrefix(server.URL, "https://127.0.0.1:")
+	u, _ := url.Parse(server.URL)
+
+	tests := []struct {
+		name     string
+		check    RemoteCheck
+		expected string
+		errPart  string
+	}{
+		{
+			name:     "trusted https",
+			check:    RemoteCheck{URL: server.URL, RootCAs: trusted},
+			expected: StatusRunning,
+		},
+		{
+			name:     "untrusted certificate",
+			check:    RemoteCheck{URL: server.URL},
+			expected: StatusDegraded,
+			errPart:  "untrusted",
+		},
+		{
+			name:     "hostname mismatch",
+			check:    RemoteCheck{URL: "https://localhost:" + port, RootCAs: trusted},
+			expected: StatusDegraded,
+			errPart:  "not valid for localhost",
+		},
+		{
+			name:     "expiring soon",
+			check:    RemoteCheck{Host: u.Host, TLS: true, RootCAs: trusted, WarnDays: 1 << 20},
+			expected: StatusDegraded,
+			errPart:  "expires in",
+		},
+		{
+			name:     "unexpected status",
+			check:    RemoteCheck{URL: server.URL + "/missing", RootCAs: trusted},
+			expected: StatusDegraded,
+			errPart:  "404",
+		},
+		{
+			name:     "plain tcp",
+			check:    RemoteCheck{Host: u.Host},
+			expected: StatusRunning,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := CheckRemote(context.Background(), tt.check)
+			if status.Status != tt.expected {
+				t.Errorf("got status %q, expected %q (err: %v)", status.Status, tt.expected, status.Error)
+			}
+			if tt.errPart != "" && (status.Error == nil || !strings.Contains(status.Error.Error(), tt.errPart)) {
+				t.Errorf("got error %v, expected it to mention %q", status.Error, tt.errPart)
+			}
+		})
+	}
+}
@@ -0,0 +1,196 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stevengregory/musing-cli/internal/docker"
+	"github.com/stevengregory/musing-cli/internal/health"
+)
+
+// LatencyBuckets are the upper bounds, in seconds, of the check latency histogram
+var LatencyBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5}
+
+// statuses lists every status, so each service exports a complete state set
+var statuses = []string{health.StatusRunning, health.StatusDegraded, health.StatusDown}
+
+// Registry accumulates health check results and container states and renders
+// them in the Prometheus text exposition format
+type Registry struct {
+	mu         sync.Mutex
+	project    string
+	order      []string
+	services   map[string]*serviceMetrics
+	containers []docker.Container
+	lastRun    time.Time
+}
+
+// serviceMetrics holds the series for one service
+type serviceMetrics struct {
+	section string
+	status  string
+	checks  map[string]int // Checks per resulting status
+	buckets []int          // Cumulative counts per LatencyBuckets bound
+	count   int
+	sum     float64
+}
+
+// NewRegistry creates an empty registry. project is added as a label to every series
+func NewRegistry(project string) *Registry {
+	return &Registry{project: project, services: map[string]*serviceMetrics{}}
+}
+
+// Observe records a check result for a service
+func (r *Registry) Observe(section string, result health.Result) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	svc, ok := r.services[result.Name]
+	if !ok {
+		svc = &serviceMetrics{checks: map[string]int{}, buckets: make([]int, len(LatencyBuckets))}
+		r.services[result.Name] = svc
+		r.order = append(r.order, result.Name)
+	}
+
+	svc.section = section
+	svc.status = result.Status
+	svc.checks[result.Status]++
+
+	// Only checks that got an answer have a meaningful latency
+	if result.Status != health.StatusDown && result.Latency > 0 {
+		seconds := result.Latency.Seconds()
+		for i, bound := range LatencyBuckets {
+			if seconds <= bound {
+				svc.buckets[i]++
+			}
+		}
+		svc.count++
+		svc.sum += seconds
+	}
+}
+
+// SetContainers replaces the known Docker container states
+func (r *Registry) SetContainers(containers []docker.Container) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.containers = containers
+}
+
+// MarkRun records the time a round of checks completed
+func (r *Registry) MarkRun(t time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastRun = t
+}
+
+// Write renders every series in the Prometheus text format
+func (r *Registry) Write(w io.Writer) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var b strings.Builder
+
+	header(&b, "musing_up", "gauge", "Whether the service answered its health check (1) or is down (0)")
+	for _, name := range r.order {
+		svc := r.services[name]
+		up := 0
+		if svc.status != health.StatusDown {
+			up = 1
+		}
+		sample(&b, "musing_up", r.labels(name, "section", svc.section), strconv.Itoa(up))
+	}
+
+	header(&b, "musing_status", "gauge", "Current status of the service, one series per possible status")
+	for _, name := range r.order {
+		svc := r.services[name]
+		for _, status := range statuses {
+			value := "0"
+			if svc.status == status {
+				value = "1"
+			}
+			sample(&b, "musing_status", r.labels(name, "status", status), value)
+		}
+	}
+
+	header(&b, "musing_checks_total", "counter", "Health checks run, by resulting status")
+	for _, name := range r.order {
+		svc := r.services[name]
+		for _, status := range statuses {
+			sample(&b, "musing_checks_total", r.labels(name, "status", status), strconv.Itoa(svc.checks[status]))
+		}
+	}
+
+	header(&b, "musing_check_latency_seconds", "histogram", "Latency of health checks that got an answer")
+	for _, name := range r.order {
+		svc := r.services[name]
+		for i, bound := range LatencyBuckets {
+			sample(&b, "musing_check_latency_seconds_bucket", r.labels(name, "le", formatFloat(bound)), strconv.Itoa(svc.buckets[i]))
+		}
+		sample(&b, "musing_check_latency_seconds_bucket", r.labels(name, "le", "+Inf"), strconv.Itoa(svc.count))
+		sample(&b, "musing_check_latency_seconds_sum", r.labels(name), formatFloat(svc.sum))
+		sample(&b, "musing_check_latency_seconds_count", r.labels(name), strconv.Itoa(svc.count))
+	}
+
+	containers := append([]docker.Container(nil), r.containers...)
+	sort.Slice(containers, func(i, j int) bool { return containers[i].Name < containers[j].Name })
+
+	header(&b, "musing_container_state", "gauge", "Docker container state (1 for the current state)")
+	for _, c := range containers {
+		labels := fmt.Sprintf(`project="%s",container="%s",service="%s",state="%s"`,
+			escape(r.project), escape(c.Name), escape(c.Service), escape(c.State))
+		sample(&b, "musing_container_state", labels, "1")
+	}
+
+	header(&b, "musing_container_healthy", "gauge", "Whether a container with a Docker healthcheck reports healthy")
+	for _, c := range containers {
+		if c.Health == "" {
+			continue
+		}
+		healthy := "0"
+		if c.Health == "healthy" {
+			healthy = "1"
+		}
+		labels := fmt.Sprintf(`project="%s",container="%s",service="%s"`, escape(r.project), escape(c.Name), escape(c.Service))
+		sample(&b, "musing_container_healthy", labels, healthy)
+	}
+
+	if !r.lastRun.IsZero() {
+		header(&b, "musing_last_check_timestamp_seconds", "gauge", "Unix time the last round of checks completed")
+		sample(&b, "musing_last_check_timestamp_seconds", fmt.Sprintf(`project="%s"`, escape(r.project)),
+			strconv.FormatInt(r.lastRun.Unix(), 10))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// labels formats the project and service labels plus optional extra name/value pairs
+func (r *Registry) labels(service string, extra ...string) string {
+	s := fmt.Sprintf(`project="%s",service="%s"`, escape(r.project), escape(service))
+	for i := 0; i+1 < len(extra); i += 2 {
+		s += fmt.Sprintf(`,%s="%s"`, extra[i], escape(extra[i+1]))
+	}
+	return s
+}
+
+func header(b *strings.Builder, name, kind, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func sample(b *strings.Builder, name, labels, value string) {
+	fmt.Fprintf(b, "%s{%s} %s\n", name, labels, value)
+}
+
+// escape escapes a label value
+func escape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,43 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stevengregory/musing-cli/internal/docker"
+	"github.com/stevengregory/musing-cli/internal/health"
+)
+
+// TestRegistryWrite tests the rendered Prometheus series
+func TestRegistryWrite(t *testing.T) {
+	r := NewRegistry("blog")
+	r.Observe("API Services", health.Result{Name: "news-api", Status: health.StatusRunning, Latency: 20 * time.Millisecond})
+	r.Observe("API Services", health.Result{Name: "news-api", Status: health.StatusDown})
+	r.Observe("Database", health.Result{Name: `say "hi"`, Status: health.StatusDegraded, Latency: 2 * time.Second})
+	r.SetContainers([]docker.Container{{Name: "blog-api-1", Service: "api", State: "running", Health: "unhealthy"}})
+
+	var b strings.Builder
+	if err := r.Write(&b); err != nil {
+		t.Fatal(err)
+	}
+	output := b.String()
+
+	expected := []string{
+		`musing_up{project="blog",service="news-api",section="API Services"} 0`,
+		`musing_status{project="blog",service="news-api",status="down"} 1`,
+		`musing_checks_total{project="blog",service="news-api",status="running"} 1`,
+		`musing_check_latency_seconds_bucket{project="blog",service="news-api",le="0.01"} 0`,
+		`musing_check_latency_seconds_bucket{project="blog",service="news-api",le="0.025"} 1`,
+		`musing_check_latency_seconds_count{project="blog",service="news-api"} 1`,
+		`musing_up{project="blog",service="say \"hi\"",section="Database"} 1`,
+		`musing_container_state{project="blog",container="blog-api-1",service="api",state="running"} 1`,
+		`musing_container_healthy{project="blog",container="blog-api-1",service="api"} 0`,
+		"# TYPE musing_check_latency_seconds histogram",
+	}
+	for _, line := range expected {
+		if !strings.Contains(output, line+"\n") {
+			t.Errorf("missing %s", line)
+		}
+	}
+}
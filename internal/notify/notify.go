@@ -0,0 +1,253 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"slices"
+	"text/template"
+	"time"
+
+	"github.com/stevengregory/musing-cli/internal/health"
+)
+
+// Event describes a service's health transition
+type Event struct {
+	Project   string        `json:"project"`
+	Service   string        `json:"service"`
+	Section   string        `json:"section"`
+	Status    string        `json:"status"`
+	Previous  string        `json:"previous"`
+	Recovered bool          `json:"recovered"` // Back to running after being degraded or down
+	Duration  time.Duration `json:"-"`         // How long the previous status lasted
+	Time      time.Time     `json:"time"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// Message formats the event as a one-line, human readable notification
+func (e Event) Message() string {
+	if e.Recovered {
+		return fmt.Sprintf("✅ [%s] %s recovered after %s %s", e.Project, e.Service, e.Duration.Round(time.Second), e.Previous)
+	}
+
+	icon := "🔴"
+	if e.Status == health.StatusDegraded {
+		icon = "🟠"
+	}
+	msg := fmt.Sprintf("%s [%s] %s is %s (was %s)", icon, e.Project, e.Service, e.Status, e.Previous)
+	if e.Error != "" {
+		msg += ": " + e.Error
+	}
+	return msg
+}
+
+// MarshalJSON adds the formatted message and duration to the event's JSON
+func (e Event) MarshalJSON() ([]byte, error) {
+	type event Event
+	return json.Marshal(struct {
+		event
+		Duration string `json:"duration"`
+		Message  string `json:"message"`
+	}{event(e), e.Duration.Round(time.Second).String(), e.Message()})
+}
+
+// Notifier delivers events somewhere
+type Notifier interface {
+	Notify(ctx context.Context, event Event) error
+}
+
+// Command runs a shell command for each event. Event fields are passed as
+// MUSING_* environment variables rather than interpolated, so they are shell safe
+type Command struct {
+	Command string
+}
+
+// Notify runs the command
+func (c Command) Notify(ctx context.Context, event Event) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", c.Command)
+	cmd.Env = append(os.Environ(),
+		"MUSING_PROJECT="+event.Project,
+		"MUSING_SERVICE="+event.Service,
+		"MUSING_SECTION="+event.Section,
+		"MUSING_STATUS="+event.Status,
+		"MUSING_PREVIOUS_STATUS="+event.Previous,
+		"MUSING_RECOVERED="+fmt.Sprint(event.Recovered),
+		"MUSING_ERROR="+event.Error,
+		"MUSING_MESSAGE="+event.Message(),
+	)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("hook %q failed: %w: %s", c.Command, err, bytes.TrimSpace(output))
+	}
+	return nil
+}
+
+// Webhook POSTs each event as JSON. Body, if set, is a template executed with the
+// event (e.g. {"text": {{json .Message}}}); otherwise the event itself is sent
+type Webhook struct {
+	URL     string
+	Body    *template.Template
+	Headers map[string]string
+}
+
+// ParseBody parses a webhook body template. The json function encodes a value as JSON
+func ParseBody(body string) (*template.Template, error) {
+	return template.New("body").Funcs(template.FuncMap{
+		"json": func(v any) (string, error) {
+			data, err := json.Marshal(v)
+			return string(data), err
+		},
+	}).Parse(body)
+}
+
+// Notify sends the webhook
+func (w Webhook) Notify(ctx context.Context, event Event) error {
+	var body bytes.Buffer
+	if w.Body != nil {
+		// Methods such as Message are available to templates through the value
+		if err := w.Body.Execute(&body, event); err != nil {
+			return fmt.Errorf("webhook body template: %w", err)
+		}
+	} else if err := json.NewEncoder(&body).Encode(event); err != nil {
+		return err
+	}
+	return post(ctx, w.URL, body.Bytes(), w.Headers)
+}
+
+// Slack posts the event message to a Slack-compatible incoming webhook
+type Slack struct {
+	URL string
+}
+
+// Notify sends the message
+func (s Slack) Notify(ctx context.Context, event Event) error {
+	body, err := json.Marshal(map[string]string{"text": event.Message()})
+	if err != nil {
+		return err
+	}
+	return post(ctx, s.URL, body, nil)
+}
+
+// post sends a JSON body and treats any non-2xx response as an error
+func post(ctx context.Context, url string, body []byte, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook failed: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned status %d", url, resp.StatusCode)
+	}
+	return nil
+}
+
+// StatusUnknown is the previous status of a service that was unhealthy when first observed
+const StatusUnknown = "unknown"
+
+// Target is a notifier, optionally limited to some services
+type Target struct {
+	Notifier Notifier
+	Services []string // Empty means every service
+}
+
+// Dispatcher turns a stream of check results into debounced transition events
+type Dispatcher struct {
+	Project  string
+	Debounce time.Duration // How long a new status must hold before notifying
+	Targets  []Target
+
+	states map[string]*serviceState
+}
+
+// serviceState tracks the last notified status of a service and any pending change
+type serviceState struct {
+	status       string
+	since        time.Time
+	pending      string
+	pendingSince time.Time
+}
+
+// Observe records a service's latest status. It returns an event once a changed
+// status has held for the debounce period, or nil. A service first observed running
+// sets its baseline without notifying; one first observed degraded or down is a change
+// from StatusUnknown, so a service that's already failing when monitoring starts alerts
+func (d *Dispatcher) Observe(service, section, status string, checkErr error, now time.Time) *Event {
+	if d.states == nil {
+		d.states = map[string]*serviceState{}
+	}
+
+	state, ok := d.states[service]
+	if !ok {
+		state = &serviceState{status: StatusUnknown, since: now}
+		if status == health.StatusRunning {
+			state.status = status
+		}
+		d.states[service] = state
+	}
+	if state.status == StatusUnknown && status == health.StatusRunning {
+		// Came up before its failure was reported, so there's nothing to recover from
+		*state = serviceState{status: status, since: now}
+		return nil
+	}
+
+	if status == state.status {
+		state.pending = ""
+		return nil
+	}
+	if status != state.pending {
+		state.pending = status
+		state.pendingSince = now
+	}
+	if now.Sub(state.pendingSince) < d.Debounce {
+		return nil
+	}
+
+	event := &Event{
+		Project:   d.Project,
+		Service:   service,
+		Section:   section,
+		Status:    status,
+		Previous:  state.status,
+		Recovered: status == health.StatusRunning,
+		Duration:  state.pendingSince.Sub(state.since),
+		Time:      now,
+	}
+	if checkErr != nil && status != health.StatusRunning {
+		event.Error = checkErr.Error()
+	}
+
+	state.status = status
+	state.since = state.pendingSince
+	state.pending = ""
+	return event
+}
+
+// Send delivers an event to every target interested in its service
+func (d *Dispatcher) Send(ctx context.Context, event Event) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var errs []error
+	for _, target := range d.Targets {
+		if len(target.Services) > 0 && !slices.Contains(target.Services, event.Service) {
+			continue
+		}
+		if err := target.Notifier.Notify(ctx, event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,124 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stevengregory/musing-cli/internal/health"
+)
+
+// TestDispatcherObserve tests baseline, debounce and recovery handling
+func TestDispatcherObserve(t *testing.T) {
+	d := &Dispatcher{Project: "blog", Debounce: 10 * time.Second}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+
+	steps := []struct {
+		seconds  int
+		status   string
+		expected string // Expected event status, or "" for none
+	}{
+		{0, health.StatusRunning, ""},   // Baseline
+		{5, health.StatusDown, ""},      // Pending
+		{8, health.StatusRunning, ""},   // Blip shorter than the debounce
+		{20, health.StatusDown, ""},     // Pending again
+		{30, health.StatusDown, "down"}, // Held for the debounce period
+		{40, health.StatusDown, ""},     // Already notified
+		{50, health.StatusRunning, ""},  // Pending recovery
+		{60, health.StatusRunning, "running"},
+	}
+
+	for _, step := range steps {
+		event := d.Observe("news-api", "API Services", step.status, nil, at(step.seconds))
+		switch {
+		case step.expected == "" && event != nil:
+			t.Errorf("t=%ds: unexpected event %+v", step.seconds, event)
+		case step.expected != "" && event == nil:
+			t.Errorf("t=%ds: expected %s event", step.seconds, step.expected)
+		case event != nil && event.Status != step.expected:
+			t.Errorf("t=%ds: event status %s, expected %s", step.seconds, event.Status, step.expected)
+		case event != nil && event.Recovered:
+			if event.Previous != health.StatusDown || event.Duration != 30*time.Second {
+				t.Errorf("recovery event = %+v, expected down for 30s", event)
+			}
+		}
+	}
+}
+
+// TestDispatcherObserveFirstFailure tests a service already down when monitoring starts
+// alerts once the debounce period passes, as a change from unknown
+func TestDispatcherObserveFirstFailure(t *testing.T) {
+	d := &Dispatcher{Project: "blog", Debounce: 10 * time.Second}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if event := d.Observe("news-api", "API Services", health.StatusDown, nil, start); event != nil {
+		t.Errorf("unexpected event before the debounce period: %+v", event)
+	}
+	event := d.Observe("news-api", "API Services", health.StatusDown, errors.New("connection refused"), start.Add(10*time.Second))
+	if event == nil {
+		t.Fatal("expected a down event for a service down from the start")
+	}
+	if event.Previous != StatusUnknown || event.Recovered || event.Error != "connection refused" {
+		t.Errorf("event = %+v, expected down from unknown", event)
+	}
+	if msg := event.Message(); !strings.Contains(msg, "news-api is down (was unknown)") {
+		t.Errorf("Message() = %q", msg)
+	}
+
+	// A service that comes up within the debounce period just sets its baseline
+	if event := d.Observe("web", "Frontend", health.StatusDown, nil, start); event != nil {
+		t.Errorf("unexpected event: %+v", event)
+	}
+	for _, seconds := range []int{5, 20} {
+		if event := d.Observe("web", "Frontend", health.StatusRunning, nil, start.Add(time.Duration(seconds)*time.Second)); event != nil {
+			t.Errorf("t=%ds: unexpected event %+v for a service that came up", seconds, event)
+		}
+	}
+}
+
+// TestWebhookNotify tests the templated webhook body and Slack payload against a local server
+func TestWebhookNotify(t *testing.T) {
+	var received []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var payload map[string]any
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Errorf("invalid JSON body %s: %v", body, err)
+		}
+		payload["auth"] = r.Header.Get("Authorization")
+		received = append(received, payload)
+	}))
+	defer server.Close()
+
+	body, err := ParseBody(`{"service": {{json .Service}}, "text": {{json .Message}}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := &Dispatcher{Targets: []Target{
+		{Notifier: Webhook{URL: server.URL, Body: body, Headers: map[string]string{"Authorization": "Bearer x"}}},
+		{Notifier: Slack{URL: server.URL}},
+		{Notifier: Slack{URL: server.URL}, Services: []string{"other"}},
+	}}
+	event := Event{Project: "blog", Service: `news "api"`, Status: health.StatusDown, Previous: health.StatusRunning, Error: "refused"}
+	if err := d.Send(context.Background(), event); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(received) != 2 {
+		t.Fatalf("got %d requests, expected 2", len(received))
+	}
+	if received[0]["service"] != `news "api"` || received[0]["auth"] != "Bearer x" {
+		t.Errorf("webhook payload = %v", received[0])
+	}
+	if received[1]["text"] != event.Message() {
+		t.Errorf("slack text = %v, expected %q", received[1]["text"], event.Message())
+	}
+}
@@ -0,0 +1,134 @@
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	blockStart = "# >>> musing secrets (%s) - managed by musing, do not edit"
+	blockEnd   = "# <<< musing secrets"
+)
+
+// WriteEnvFile writes values into a managed block of the .env file at path,
+// preserving any other lines already in the file
+func WriteEnvFile(path, env string, values map[string]string) error {
+	existing, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	kept := stripManagedBlock(string(existing))
+
+	var b strings.Builder
+	b.WriteString(kept)
+	if kept != "" && !strings.HasSuffix(kept, "\n") {
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, blockStart+"\n", env)
+	for _, name := range Names(values) {
+		fmt.Fprintf(&b, "%s=%s\n", name, quoteEnvValue(values[name]))
+	}
+	b.WriteString(blockEnd + "\n")
+
+	return os.WriteFile(path, []byte(b.String()), 0600)
+}
+
+// stripManagedBlock removes a previously written musing block from .env contents
+func stripManagedBlock(contents string) string {
+	var kept []string
+	inBlock := false
+	for _, line := range strings.SplitAfter(contents, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "# >>> musing secrets"):
+			inBlock = true
+		case trimmed == blockEnd:
+			inBlock = false
+		case !inBlock:
+			kept = append(kept, line)
+		}
+	}
+	return strings.Join(kept, "")
+}
+
+// quoteEnvValue quotes values that compose would otherwise misread. Compose expands
+// $VAR inside double quotes, so values with a $ are single-quoted, or have the $
+// escaped when they can't be
+func quoteEnvValue(value string) string {
+	switch {
+	case strings.Contains(value, "$") && !strings.ContainsAny(value, "'\n"):
+		return "'" + value + "'"
+	case value == "" || strings.ContainsAny(value, " \t\n\"'#$\\"):
+		return strings.ReplaceAll(strconv.Quote(value), "$", `\$`)
+	}
+	return value
+}
+
+// ReadEnvNames returns the variable names defined in a .env file, ignoring
+// comments and blank lines. A missing file defines nothing
+func ReadEnvNames(path string) (map[string]bool, error) {
+	names := map[string]bool{}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return names, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+		if name, _, ok := strings.Cut(line, "="); ok {
+			names[strings.TrimSpace(name)] = true
+		}
+	}
+	return names, nil
+}
+
+// composeVarPattern matches ${VAR}, ${VAR:-default}, ${VAR?err} and $VAR references
+var composeVarPattern = regexp.MustCompile(`\$(?:\{([A-Za-z_][A-Za-z0-9_]*)(:?[-?+][^}]*)?\}|([A-Za-z_][A-Za-z0-9_]*))`)
+
+// RequiredComposeVars returns variables referenced by the compose files that have no
+// default value (e.g. ${API_KEY} or ${API_KEY:?missing}, but not ${PORT:-8080})
+func RequiredComposeVars(files []string) ([]string, error) {
+	required := map[string]bool{}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		// $$ is an escaped literal dollar sign in compose files
+		contents := strings.ReplaceAll(string(data), "$$", "")
+		for _, match := range composeVarPattern.FindAllStringSubmatch(contents, -1) {
+			name, modifier := match[1], match[2]
+			if name == "" {
+				name = match[3]
+			}
+			if strings.HasPrefix(modifier, "-") || strings.HasPrefix(modifier, ":-") ||
+				strings.HasPrefix(modifier, "+") || strings.HasPrefix(modifier, ":+") {
+				continue // Has a default (or is only used when set)
+			}
+			required[name] = true
+		}
+	}
+
+	names := make([]string, 0, len(required))
+	for name := range required {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,220 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+const (
+	kdfPassphrase = "pbkdf2-sha256"
+	kdfKeyFile    = "keyfile"
+
+	// pbkdf2Iterations follows current OWASP guidance for PBKDF2-HMAC-SHA256
+	pbkdf2Iterations = 600000
+
+	keySize        = 32
+	keyFilePrefix  = "MUSING-SECRET-KEY-"
+	keyFileComment = "# musing secrets key - keep this file private and out of git"
+)
+
+// Key unlocks a secrets file using either a key file or a passphrase
+type Key struct {
+	raw        []byte // Key file contents (nil when using a passphrase)
+	passphrase string
+}
+
+// envelope is the on-disk format of an encrypted secrets file
+type envelope struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Salt       string `json:"salt,omitempty"`
+	Iterations int    `json:"iterations,omitempty"`
+	Nonce      string `json:"nonce"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// KeyFromPassphrase returns a key derived from a passphrase
+func KeyFromPassphrase(passphrase string) Key {
+	return Key{passphrase: passphrase}
+}
+
+// KeyFromFile reads a key file created by GenerateKeyFile
+func KeyFromFile(path string) (Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Key{}, err
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, keyFilePrefix) {
+			continue
+		}
+		raw, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(line, keyFilePrefix))
+		if err != nil || len(raw) != keySize {
+			return Key{}, fmt.Errorf("invalid key in %s", path)
+		}
+		return Key{raw: raw}, nil
+	}
+
+	return Key{}, fmt.Errorf("no %s line found in %s", keyFilePrefix, path)
+}
+
+// GenerateKeyFile writes a new random key to path, refusing to overwrite an existing file
+func GenerateKeyFile(path string) error {
+	raw := make([]byte, keySize)
+	if _, err := rand.Read(raw); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintf(file, "%s\n%s%s\n", keyFileComment, keyFilePrefix, base64.RawURLEncoding.EncodeToString(raw))
+	return err
+}
+
+// Load decrypts the secrets file at path. A missing file yields an empty set
+func Load(path string, key Key) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	switch {
+	case env.KDF == kdfKeyFile && key.raw == nil:
+		return nil, fmt.Errorf("%s is encrypted with a key file, not a passphrase", filepath.Base(path))
+	case env.KDF == kdfPassphrase && key.raw != nil:
+		return nil, fmt.Errorf("%s is encrypted with a passphrase, not a key file", filepath.Base(path))
+	case env.KDF != kdfKeyFile && env.KDF != kdfPassphrase:
+		return nil, fmt.Errorf("unsupported key derivation %q in %s", env.KDF, path)
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(env.Salt)
+	if err != nil {
+		return nil, fmt.Errorf("corrupt salt in %s", path)
+	}
+	nonce, err := base64.StdEncoding.DecodeString(env.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("corrupt nonce in %s", path)
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(env.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("corrupt ciphertext in %s", path)
+	}
+
+	aead, err := key.aead(salt, env.Iterations)
+	if err != nil {
+		return nil, err
+	}
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt %s: wrong passphrase or key", filepath.Base(path))
+	}
+
+	values := map[string]string{}
+	if err := json.Unmarshal(plaintext, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse decrypted secrets: %w", err)
+	}
+	return values, nil
+}
+
+// Save encrypts values and writes them to path
+func Save(path string, key Key, values map[string]string) error {
+	plaintext, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+
+	env := envelope{Version: 1, KDF: kdfKeyFile}
+	var salt []byte
+	if key.raw == nil {
+		env.KDF = kdfPassphrase
+		env.Iterations = pbkdf2Iterations
+		salt = make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return err
+		}
+		env.Salt = base64.StdEncoding.EncodeToString(salt)
+	}
+
+	aead, err := key.aead(salt, env.Iterations)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	env.Nonce = base64.StdEncoding.EncodeToString(nonce)
+	env.Ciphertext = base64.StdEncoding.EncodeToString(aead.Seal(nil, nonce, plaintext, nil))
+
+	data, err := json.MarshalIndent(env, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0600)
+}
+
+// Names returns the secret names in sorted order
+func Names(values map[string]string) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// aead builds the AES-256-GCM cipher for this key
+func (k Key) aead(salt []byte, iterations int) (cipher.AEAD, error) {
+	raw := k.raw
+	if raw == nil {
+		if k.passphrase == "" {
+			return nil, fmt.Errorf("empty passphrase")
+		}
+		derived, err := pbkdf2.Key(sha256.New, k.passphrase, salt, iterations, keySize)
+		if err != nil {
+			return nil, err
+		}
+		raw = derived
+	}
+
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,163 @@
+package secrets
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestSaveLoadRoundTrip tests encryption with passphrases and key files
+func TestSaveLoadRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "musing.key")
+	if err := GenerateKeyFile(keyPath); err != nil {
+		t.Fatal(err)
+	}
+	fileKey, err := KeyFromFile(keyPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		key      Key
+		wrongKey Key
+	}{
+		{name: "passphrase", key: KeyFromPassphrase("correct horse"), wrongKey: KeyFromPassphrase("battery staple")},
+		{name: "key file", key: fileKey, wrongKey: KeyFromPassphrase("correct horse")},
+	}
+
+	values := map[string]string{"API_KEY": "abc123", "DB_PASSWORD": "p@ss word"}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.name+".enc")
+			if err := Save(path, tt.key, values); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+
+			data, _ := os.ReadFile(path)
+			if strings.Contains(string(data), "abc123") {
+				t.Error("secrets file contains plaintext value")
+			}
+
+			loaded, err := Load(path, tt.key)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if !reflect.DeepEqual(loaded, values) {
+				t.Errorf("Load() = %v, want %v", loaded, values)
+			}
+
+			if _, err := Load(path, tt.wrongKey); err == nil {
+				t.Error("Load() with wrong key succeeded, want error")
+			}
+		})
+	}
+}
+
+// TestWriteEnvFile tests that the managed block is replaced and other lines are kept
+func TestWriteEnvFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte("PORT=8080\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteEnvFile(path, "dev", map[string]string{"API_KEY": "old"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteEnvFile(path, "dev", map[string]string{"API_KEY": "new value"}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contents := string(data)
+	if !strings.HasPrefix(contents, "PORT=8080\n") {
+		t.Errorf("existing lines not preserved:\n%s", contents)
+	}
+	if strings.Contains(contents, "old") || strings.Count(contents, blockEnd) != 1 {
+		t.Errorf("managed block not replaced:\n%s", contents)
+	}
+	if !strings.Contains(contents, `API_KEY="new value"`) {
+		t.Errorf("value not quoted:\n%s", contents)
+	}
+}
+
+// TestQuoteEnvValue tests values read back the same under compose's .env quoting rules
+func TestQuoteEnvValue(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected string
+	}{
+		{value: "plain", expected: "plain"},
+		{value: "", expected: `""`},
+		{value: "new value", expected: `"new value"`},
+		{value: "pa$word", expected: `'pa$word'`},
+		{value: "it's $HOME", expected: `"it's \$HOME"`},
+		{value: "line\n$1", expected: `"line\n\$1"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			quoted := quoteEnvValue(tt.value)
+			if quoted != tt.expected {
+				t.Errorf("quoteEnvValue(%q) = %s, expected %s", tt.value, quoted, tt.expected)
+			}
+			if got := readComposeValue(t, quoted); got != tt.value {
+				t.Errorf("compose would read %s as %q, expected %q", quoted, got, tt.value)
+			}
+		})
+	}
+}
+
+// readComposeValue reads a .env value the way compose does: single quotes are literal,
+// and double quotes take escapes, including \$ for a literal $
+func readComposeValue(t *testing.T, quoted string) string {
+	t.Helper()
+	switch {
+	case len(quoted) >= 2 && quoted[0] == '\'' && quoted[len(quoted)-1] == '\'':
+		return quoted[1 : len(quoted)-1]
+	case strings.HasPrefix(quoted, `"`):
+		if strings.Contains(strings.ReplaceAll(quoted, `\$`, ""), "$") {
+			t.Errorf("%s has an unescaped $ compose would expand", quoted)
+		}
+		value, err := strconv.Unquote(strings.ReplaceAll(quoted, `\$`, "$"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return value
+	}
+	return quoted
+}
+
+// TestRequiredComposeVars tests detection of variables without defaults
+func TestRequiredComposeVars(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "compose.yaml")
+	compose := `services:
+  api:
+    environment:
+      API_KEY: ${API_KEY}
+      TOKEN: ${TOKEN:?token required}
+      PORT: ${PORT:-8080}
+      DEBUG: ${DEBUG-false}
+      REGION: $REGION
+      LITERAL: $$HOME
+`
+	if err := os.WriteFile(path, []byte(compose), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	names, err := RequiredComposeVars([]string{path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"API_KEY", "REGION", "TOKEN"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("RequiredComposeVars() = %v, want %v", names, expected)
+	}
+}
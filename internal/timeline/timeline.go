@@ -0,0 +1,241 @@
+package timeline
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// Event kinds
+const (
+	KindStatus    = "status"    // A service's health check changed status
+	KindContainer = "container" // Docker reported a container event
+	KindTunnel    = "tunnel"    // The SSH tunnel dropped or came back
+	KindDeploy    = "deploy"    // 'musing deploy' ran
+)
+
+// Event levels, which set how an event is highlighted
+const (
+	LevelInfo  = "info"
+	LevelOK    = "ok"
+	LevelWarn  = "warn"
+	LevelError = "error"
+)
+
+// DefaultSize is the number of events kept
+const DefaultSize = 500
+
+// compactSize is the file size above which Append rewrites the file with the last DefaultSize events
+const compactSize = 1 << 20
+
+// Event is something that happened to the stack
+type Event struct {
+	Time     time.Time     `json:"time"`
+	Kind     string        `json:"kind"`
+	Service  string        `json:"service,omitempty"`
+	Message  string        `json:"message"`
+	Level    string        `json:"level,omitempty"`
+	Duration time.Duration `json:"duration,omitempty"` // How long the previous state lasted, or how long a deploy took
+}
+
+// Timeline keeps the most recent events in time order
+type Timeline struct {
+	size   int
+	events []Event
+}
+
+// New creates an empty timeline keeping up to size events
+func New(size int) *Timeline {
+	if size <= 0 {
+		size = DefaultSize
+	}
+	return &Timeline{size: size}
+}
+
+// Add adds events, keeping the timeline in time order
+func (t *Timeline) Add(events ...Event) {
+	for _, event := range events {
+		i := len(t.events)
+		for i > 0 && t.events[i-1].Time.After(event.Time) {
+			i--
+		}
+		t.events = slices.Insert(t.events, i, event)
+	}
+	if over := len(t.events) - t.size; over > 0 {
+		t.events = append([]Event(nil), t.events[over:]...)
+	}
+}
+
+// Events returns the kept events, oldest first
+func (t *Timeline) Events() []Event {
+	return t.events
+}
+
+// Len returns the number of kept events
+func (t *Timeline) Len() int {
+	return len(t.events)
+}
+
+// Load reads the last size events from a file written by Append, returning the offset
+// to pass to ReadFrom for events appended later. A missing file gives an empty timeline
+func Load(path string, size int) (*Timeline, int64, error) {
+	t := New(size)
+	events, offset, err := ReadFrom(path, 0)
+	if err != nil {
+		return nil, 0, err
+	}
+	t.Add(events...)
+	return t, offset, nil
+}
+
+// ReadFrom reads the events appended to a file since offset, returning the new offset.
+// If the file was compacted since, events written in between are skipped
+func ReadFrom(path string, offset int64) ([]Event, int64, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, offset, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, offset, err
+	}
+	if info.Size() < offset {
+		return nil, info.Size(), nil
+	}
+	if info.Size() == offset {
+		return nil, offset, nil
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return nil, offset, err
+	}
+
+	// Only whole lines are consumed, so a write in progress is picked up next time
+	var events []Event
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			break
+		}
+		offset += int64(len(line))
+
+		var event Event
+		if json.Unmarshal(line, &event) == nil {
+			events = append(events, event) // Lines torn by an interrupted write are skipped
+		}
+	}
+	return events, offset, nil
+}
+
+// Append writes events to a timeline file, one JSON object per line. The file is
+// compacted to the last DefaultSize events once it grows past compactSize
+func Append(path string, events ...Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if err := writeEvents(file, events); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if info, err := os.Stat(path); err == nil && info.Size() > compactSize {
+		return compact(path)
+	}
+	return nil
+}
+
+// compact rewrites a timeline file with only its last DefaultSize events
+func compact(path string) error {
+	t, _, err := Load(path, DefaultSize)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if err := writeEvents(file, t.Events()); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// writeEvents writes events as JSON lines
+func writeEvents(w io.Writer, events []Event) error {
+	writer := bufio.NewWriter(w)
+	encoder := json.NewEncoder(writer)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return err
+		}
+	}
+	return writer.Flush()
+}
+
+// Export writes events as plain text, one per line, e.g.
+// "2024-05-01 10:00:00  status     my-api  running → down  (after 3h2m0s)"
+func Export(w io.Writer, events []Event) error {
+	for _, event := range events {
+		if _, err := fmt.Fprintln(w, Format(event)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Format formats an event as a line of plain text
+func Format(event Event) string {
+	line := fmt.Sprintf("%s  %-9s  ", event.Time.Format("2006-01-02 15:04:05"), event.Kind)
+	if event.Service != "" {
+		line += event.Service + "  "
+	}
+	line += event.Message
+	if d := FormatDuration(event); d != "" {
+		line += "  (" + d + ")"
+	}
+	return line
+}
+
+// FormatDuration describes an event's duration: how long a deploy took, or how
+// long the state before the event lasted. Empty when there's no duration
+func FormatDuration(event Event) string {
+	if event.Duration <= 0 {
+		return ""
+	}
+	d := event.Duration.Round(time.Second)
+	if event.Duration < time.Second {
+		d = event.Duration.Round(time.Millisecond)
+	}
+	if event.Kind == KindDeploy {
+		return "took " + d.String()
+	}
+	return "after " + d.String()
+}
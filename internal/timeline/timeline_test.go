@@ -0,0 +1,89 @@
+package timeline
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestTimelineAdd tests events are kept in time order and trimmed to size
+func TestTimelineAdd(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tl := New(3)
+	for _, offset := range []int{1, 3, 2, 0} {
+		tl.Add(Event{Time: start.Add(time.Duration(offset) * time.Second), Message: "event"})
+	}
+
+	events := tl.Events()
+	if len(events) != 3 {
+		t.Fatalf("kept %d events, expected 3", len(events))
+	}
+	for i, event := range events {
+		if expected := start.Add(time.Duration(i+1) * time.Second); !event.Time.Equal(expected) {
+			t.Errorf("event %d at %v, expected %v", i, event.Time, expected)
+		}
+	}
+}
+
+// TestReadFrom tests events appended to the file are picked up from an offset
+func TestReadFrom(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "timeline.db")
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tl, offset, err := Load(path, 0)
+	if err != nil || tl.Len() != 0 || offset != 0 {
+		t.Fatalf("Load of a missing file = %d events at %d, %v", tl.Len(), offset, err)
+	}
+
+	first := Event{Time: start, Kind: KindDeploy, Service: "mongodb", Message: "deployed all to dev", Duration: 2 * time.Second}
+	if err := Append(path, first); err != nil {
+		t.Fatal(err)
+	}
+	events, offset, err := ReadFrom(path, offset)
+	if err != nil || len(events) != 1 || events[0] != first {
+		t.Fatalf("ReadFrom = %+v, %v, expected the deploy", events, err)
+	}
+
+	// A torn line is skipped until it's completed
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.WriteString(`{"kind":"status"`)
+	file.Close()
+	if events, next, _ := ReadFrom(path, offset); len(events) != 0 || next != offset {
+		t.Errorf("ReadFrom with a partial line = %d events at %d, expected none at %d", len(events), next, offset)
+	}
+
+	// A file compacted below the offset skips to its end
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if events, next, _ := ReadFrom(path, offset); len(events) != 0 || next != 0 {
+		t.Errorf("ReadFrom after truncation = %d events at %d, expected none at 0", len(events), next)
+	}
+}
+
+// TestExport tests the plain text format of exported events
+func TestExport(t *testing.T) {
+	at := time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC)
+	events := []Event{
+		{Time: at, Kind: KindStatus, Service: "my-api", Message: "running → down", Duration: 3*time.Hour + 2*time.Minute},
+		{Time: at, Kind: KindDeploy, Message: "deployed all to prod", Duration: 1500 * time.Millisecond},
+		{Time: at, Kind: KindContainer, Service: "my-api", Message: "container restarted"},
+	}
+
+	var buf bytes.Buffer
+	if err := Export(&buf, events); err != nil {
+		t.Fatal(err)
+	}
+	expected := "2026-01-01 10:00:00  status     my-api  running → down  (after 3h2m0s)\n" +
+		"2026-01-01 10:00:00  deploy     deployed all to prod  (took 2s)\n" +
+		"2026-01-01 10:00:00  container  my-api  container restarted\n"
+	if buf.String() != expected {
+		t.Errorf("Export =\n%s\nexpected\n%s", buf.String(), expected)
+	}
+}
@@ -0,0 +1,22 @@
+package ui
+
+import (
+	"github.com/charmbracelet/huh"
+)
+
+// Password prompts for a secret value without echoing it
+// Returns an error if the user cancels the prompt
+func Password(title string) (string, error) {
+	var value string
+
+	input := huh.NewInput().
+		Title(title).
+		EchoMode(huh.EchoModePassword).
+		Value(&value)
+
+	if err := input.Run(); err != nil {
+		return "", err
+	}
+
+	return value, nil
+}
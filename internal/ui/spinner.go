@@ -123,7 +123,7 @@ func (m spinnerModel) View() string {
 // Falls back to Gum if no TTY is available
 func SpinWithBubbles(message string, command string, args ...string) error {
 	// Check if we have a TTY - if not, fall back to Gum
-	if !isTTY() {
+	if !IsTTY() {
 		return Spin(message, command, args...)
 	}
 
@@ -144,8 +144,8 @@ func SpinWithBubbles(message string, command string, args ...string) error {
 	return nil
 }
 
-// isTTY checks if stdout is a terminal
-func isTTY() bool {
+// IsTTY checks if stdout is a terminal
+func IsTTY() bool {
 	fileInfo, _ := os.Stdout.Stat()
 	return (fileInfo.Mode() & os.ModeCharDevice) != 0
 }